@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"plusplusbot/admin"
+	"plusplusbot/infra/repository"
+)
+
+const adminUsage = `Usage: plusplusbot admin [options] <command> [arguments]
+
+Commands:
+  get <target>            show the points of a target
+  set <target> <points>   set the points of a target
+  adjust <target> <delta> add delta (may be negative) to the points of a target
+  reset <target>          set the points of a target to zero
+  delete <target>         delete a target
+  rename <from> <to>      move the points of a target to a new target
+  merge <from> <to>       add the points of a target to another and delete the source
+  list [limit]            list targets ordered by points
+  season start [name]     start a season, named after today's date by default
+  season end              end the season in progress and archive its standings
+
+Targets are user IDs (U123456, <@U123456>) or emoji names (sake, :sake:).
+Every mutation is recorded in the audit log (AUDIT_LOG_PATH).
+
+Options:
+`
+
+// runAdmin runs an admin subcommand against the configured repository
+func runAdmin(args []string) int {
+	fs := flag.NewFlagSet("admin", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), adminUsage)
+		fs.PrintDefaults()
+	}
+	actor := fs.String("actor", defaultActor(), "name recorded as the actor in the audit log")
+	reason := fs.String("reason", "", "reason recorded in the audit log and the point history")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, logger := loadConfig(os.Stderr)
+	if cfg == nil {
+		return 1
+	}
+	if err := cfg.Validate(); err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		return 1
+	}
+
+	repo, err := repository.NewRepository(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to create repository", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Error("Failed to close repository", "error", err)
+		}
+	}()
+
+	auditFile, err := os.OpenFile(cfg.AuditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		logger.Error("Failed to open audit log", "error", err)
+		return 1
+	}
+	defer auditFile.Close()
+
+	a := admin.New(repo, admin.NewAuditLog(auditFile), *actor).WithReason(*reason)
+	if err := runAdminCommand(context.Background(), a, cfg.Location(), fs.Arg(0), fs.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// errUsage indicates that an admin command was called with invalid arguments
+var errUsage = errors.New("invalid arguments")
+
+// runAdminCommand runs a single admin command and prints its result. Seasons are named after dates in location.
+func runAdminCommand(ctx context.Context, a *admin.Admin, location *time.Location, command string, args []string) error {
+	wantArgs := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("%s expects %d argument(s): %w", command, n, errUsage)
+		}
+		return nil
+	}
+	intArg := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number: %w", s, errUsage)
+		}
+		return n, nil
+	}
+
+	switch command {
+	case "get":
+		if err := wantArgs(1); err != nil {
+			return err
+		}
+		record, err := a.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		printUserPoints([]repository.UserPoints{*record})
+	case "set":
+		if err := wantArgs(2); err != nil {
+			return err
+		}
+		points, err := intArg(args[1])
+		if err != nil {
+			return err
+		}
+		points, err = a.Set(ctx, args[0], points)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d\n", args[0], points)
+	case "adjust":
+		if err := wantArgs(2); err != nil {
+			return err
+		}
+		delta, err := intArg(args[1])
+		if err != nil {
+			return err
+		}
+		points, err := a.Adjust(ctx, args[0], delta)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %d\n", args[0], points)
+	case "reset":
+		if err := wantArgs(1); err != nil {
+			return err
+		}
+		if err := a.Reset(ctx, args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("%s: 0\n", args[0])
+	case "delete":
+		if err := wantArgs(1); err != nil {
+			return err
+		}
+		if err := a.Delete(ctx, args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("%s: deleted\n", args[0])
+	case "rename":
+		if err := wantArgs(2); err != nil {
+			return err
+		}
+		if err := a.Rename(ctx, args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s\n", args[0], args[1])
+	case "merge":
+		if err := wantArgs(2); err != nil {
+			return err
+		}
+		points, err := a.Merge(ctx, args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s -> %s: %d\n", args[0], args[1], points)
+	case "list":
+		limit := 0
+		if len(args) > 1 {
+			return fmt.Errorf("list expects at most 1 argument: %w", errUsage)
+		}
+		if len(args) == 1 {
+			n, err := intArg(args[0])
+			if err != nil {
+				return err
+			}
+			limit = n
+		}
+		records, err := a.List(ctx, limit)
+		if err != nil {
+			return err
+		}
+		printUserPoints(records)
+	case "season":
+		switch {
+		case len(args) >= 1 && len(args) <= 2 && args[0] == "start":
+			name := ""
+			if len(args) == 2 {
+				name = args[1]
+			}
+			season, err := a.StartSeason(ctx, name, time.Now().In(location))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: started\n", season.Name)
+		case len(args) == 1 && args[0] == "end":
+			season, standings, err := a.EndSeason(ctx, time.Now().In(location))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s: ended\n", season.Name)
+			printStandings(standings)
+		default:
+			return fmt.Errorf("season expects start [name] or end: %w", errUsage)
+		}
+	default:
+		return fmt.Errorf("unknown command %q: %w", command, errUsage)
+	}
+	return nil
+}
+
+// printUserPoints prints points records as a table
+func printUserPoints(records []repository.UserPoints) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TARGET\tPOINTS\tIS_USER\tLAST_MODIFIED")
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%d\t%t\t%s\n", r.UserID, r.Points, r.IsUser, r.LastModified.Format(time.RFC3339))
+	}
+	w.Flush()
+}
+
+// printStandings prints the final standings of a season as a table
+func printStandings(standings []repository.UserPoints) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TARGET\tPOINTS\tIS_USER")
+	for _, r := range standings {
+		fmt.Fprintf(w, "%s\t%d\t%t\n", r.UserID, r.Points, r.IsUser)
+	}
+	w.Flush()
+}
+
+// defaultActor returns the name of the OS user running the command
+func defaultActor() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
@@ -0,0 +1,313 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"plusplusbot/infra/repository"
+	"plusplusbot/transfer"
+)
+
+// userIDPattern matches Slack user IDs
+var userIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]{2,}$`)
+
+// Admin provides administrative operations on points. Every mutation is recorded in the audit log,
+// and every point change in the point history with the actor as the giver.
+type Admin struct {
+	repo   repository.UserPointsRepository
+	audit  *AuditLog
+	actor  string
+	reason string
+}
+
+// New creates a new Admin acting as the given actor
+func New(repo repository.UserPointsRepository, audit *AuditLog, actor string) *Admin {
+	return &Admin{
+		repo:  repo,
+		audit: audit,
+		actor: actor,
+	}
+}
+
+// WithReason returns a copy of the Admin recording reason in the audit log and the point history
+func (a *Admin) WithReason(reason string) *Admin {
+	c := *a
+	c.reason = reason
+	return &c
+}
+
+// ParseTarget normalizes a target given as "<@U123>", "@U123", ":emoji:" or a bare ID,
+// and reports whether it looks like a user
+func ParseTarget(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, "<@") && strings.HasSuffix(s, ">"):
+		return strings.TrimSuffix(strings.TrimPrefix(s, "<@"), ">"), true
+	case strings.HasPrefix(s, "@"):
+		return strings.TrimPrefix(s, "@"), true
+	case len(s) > 2 && strings.HasPrefix(s, ":") && strings.HasSuffix(s, ":"):
+		return strings.Trim(s, ":"), false
+	default:
+		return s, userIDPattern.MatchString(s)
+	}
+}
+
+// lookup returns the normalized target, its current record (nil if there is none),
+// and whether it should be treated as a user
+func (a *Admin) lookup(ctx context.Context, target string) (string, *repository.UserPoints, bool, error) {
+	id, isUser := ParseTarget(target)
+	if id == "" {
+		return "", nil, false, fmt.Errorf("target is empty")
+	}
+
+	record, err := a.repo.GetUserPoints(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return id, nil, isUser, nil
+	}
+	if err != nil {
+		return "", nil, false, err
+	}
+	return id, record, record.IsUser, nil
+}
+
+// record writes an entry to the audit log. The change it records has already been applied, so a failure
+// says so rather than reading as if nothing happened.
+func (a *Admin) record(action, target, source string, before, after int) error {
+	if err := a.audit.Record(AuditEntry{
+		Actor:  a.actor,
+		Action: action,
+		Target: target,
+		Source: source,
+		Before: before,
+		After:  after,
+		Reason: a.reason,
+	}); err != nil {
+		return fmt.Errorf("%s of %s was applied (%d -> %d) but failed to write audit log: %w", action, target, before, after, err)
+	}
+	return nil
+}
+
+// history records a point change in the point history, with the actor as the giver. The reason is the one
+// given, or the action if none was. Changes of zero points are not recorded.
+func (a *Admin) history(ctx context.Context, action, target string, delta int) error {
+	if delta == 0 {
+		return nil
+	}
+	reason := a.reason
+	if reason == "" {
+		reason = "admin " + action
+	}
+	if err := a.repo.AddPointEvent(ctx, repository.PointEvent{
+		Target:    target,
+		Timestamp: time.Now(),
+		Giver:     a.actor,
+		Delta:     delta,
+		Reason:    reason,
+	}); err != nil {
+		return fmt.Errorf("%s of %s was applied but failed to record it in the point history: %w", action, target, err)
+	}
+	return nil
+}
+
+// Get gets the points record for a target, or repository.ErrNotFound if there is none
+func (a *Admin) Get(ctx context.Context, target string) (*repository.UserPoints, error) {
+	id, record, _, err := a.lookup(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, fmt.Errorf("%s: %w", id, repository.ErrNotFound)
+	}
+	return record, nil
+}
+
+// Set sets the points of a target and returns the new total, which the point policy may have floored
+func (a *Admin) Set(ctx context.Context, target string, points int) (int, error) {
+	id, record, isUser, err := a.lookup(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+
+	before := 0
+	if record != nil {
+		before = record.Points
+	}
+
+	if err := a.repo.SetPoints(ctx, id, points, isUser); err != nil {
+		return 0, err
+	}
+
+	after, err := a.repo.GetPoints(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := a.history(ctx, "set", id, after-before); err != nil {
+		return after, err
+	}
+	return after, a.record("set", id, "", before, after)
+}
+
+// Adjust adds delta to the points of a target and returns the new total
+func (a *Admin) Adjust(ctx context.Context, target string, delta int) (int, error) {
+	id, record, isUser, err := a.lookup(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+
+	before := 0
+	if record != nil {
+		before = record.Points
+	}
+
+	if err := a.repo.AddPoints(ctx, id, delta, isUser); err != nil {
+		return 0, err
+	}
+
+	after, err := a.repo.GetPoints(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := a.history(ctx, "adjust", id, after-before); err != nil {
+		return after, err
+	}
+	return after, a.record("adjust", id, "", before, after)
+}
+
+// Reset sets the points of a target to zero
+func (a *Admin) Reset(ctx context.Context, target string) error {
+	id, record, isUser, err := a.lookup(ctx, target)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("%s: %w", id, repository.ErrNotFound)
+	}
+
+	if err := a.repo.SetPoints(ctx, id, 0, isUser); err != nil {
+		return err
+	}
+	if err := a.history(ctx, "reset", id, -record.Points); err != nil {
+		return err
+	}
+	return a.record("reset", id, "", record.Points, 0)
+}
+
+// Delete deletes the points record of a target
+func (a *Admin) Delete(ctx context.Context, target string) error {
+	id, record, _, err := a.lookup(ctx, target)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("%s: %w", id, repository.ErrNotFound)
+	}
+
+	if err := a.repo.DeletePoints(ctx, id); err != nil {
+		return err
+	}
+	if err := a.history(ctx, "delete", id, -record.Points); err != nil {
+		return err
+	}
+	return a.record("delete", id, "", record.Points, 0)
+}
+
+// Rename moves the points of a target to a new target that must not exist yet
+func (a *Admin) Rename(ctx context.Context, from, to string) error {
+	_, err := a.move(ctx, "rename", from, to, false)
+	return err
+}
+
+// Merge adds the points of a target to another target, deletes the source,
+// and returns the new total of the destination
+func (a *Admin) Merge(ctx context.Context, from, to string) (int, error) {
+	return a.move(ctx, "merge", from, to, true)
+}
+
+// move moves the points of from into to and deletes from, in one repository transaction
+func (a *Admin) move(ctx context.Context, action, from, to string, merge bool) (int, error) {
+	fromID, source, _, err := a.lookup(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+	if source == nil {
+		return 0, fmt.Errorf("%s: %w", fromID, repository.ErrNotFound)
+	}
+
+	toID, dest, isUser, err := a.lookup(ctx, to)
+	if err != nil {
+		return 0, err
+	}
+	if fromID == toID {
+		return 0, fmt.Errorf("cannot %s %s into itself", action, fromID)
+	}
+	if dest != nil && !merge {
+		return 0, fmt.Errorf("%s already exists; use merge instead", toID)
+	}
+
+	before := 0
+	if dest != nil {
+		before = dest.Points
+	}
+
+	// Both records change in one transaction, so that a failure never leaves the points in both
+	total := repository.UserPoints{UserID: toID, Points: before + source.Points, IsUser: isUser}
+	if err := a.repo.MovePoints(ctx, fromID, total); err != nil {
+		return 0, err
+	}
+
+	after, err := a.repo.GetPoints(ctx, toID)
+	if err != nil {
+		return 0, err
+	}
+	if err := a.history(ctx, action, fromID, -source.Points); err != nil {
+		return after, err
+	}
+	if err := a.history(ctx, action, toID, after-before); err != nil {
+		return after, err
+	}
+	return after, a.record(action, toID, fromID, before, after)
+}
+
+// List lists points records ordered by points, highest first
+func (a *Admin) List(ctx context.Context, limit int) ([]repository.UserPoints, error) {
+	return a.repo.ListPoints(ctx, limit)
+}
+
+// StartSeason starts a season at start, named after its start date if name is empty
+func (a *Admin) StartSeason(ctx context.Context, name string, start time.Time) (*repository.Season, error) {
+	if name == "" {
+		name = start.Format(time.DateOnly)
+	}
+	season, err := repository.StartSeason(ctx, a.repo, name, start)
+	if err != nil {
+		return nil, err
+	}
+	return season, a.record("season-start", season.Name, "", 0, 0)
+}
+
+// EndSeason ends the season in progress at end, archiving its standings, and returns the season with them
+func (a *Admin) EndSeason(ctx context.Context, end time.Time) (*repository.Season, []repository.UserPoints, error) {
+	season, standings, err := repository.EndSeason(ctx, a.repo, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	return season, standings, a.record("season-end", season.Name, "", 0, 0)
+}
+
+// MergeKarma applies a plan merging scores from another karma bot, recording every target it changes
+func (a *Admin) MergeKarma(ctx context.Context, plan *transfer.MergePlan) error {
+	return plan.Apply(ctx, a.repo, func(item transfer.MergeItem) error {
+		after, err := a.repo.GetPoints(ctx, item.Target)
+		if err != nil {
+			return err
+		}
+		if err := a.history(ctx, "import-karma", item.Target, after-item.Current); err != nil {
+			return err
+		}
+		return a.record("import-karma", item.Target, strings.Join(item.Sources, ","), item.Current, after)
+	})
+}
@@ -0,0 +1,357 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"log/slog"
+	"plusplusbot/infra/repository"
+	"plusplusbot/transfer"
+)
+
+func setupTestAdmin(t *testing.T) (*Admin, repository.UserPointsRepository, *bytes.Buffer, func()) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	repo, err := repository.NewSQLiteRepository(":memory:", logger)
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+
+	audit := &bytes.Buffer{}
+	a := New(repo, NewAuditLog(audit), "tester")
+
+	cleanup := func() {
+		if err := repo.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}
+
+	return a, repo, audit, cleanup
+}
+
+func auditEntries(t *testing.T, audit *bytes.Buffer) []AuditEntry {
+	var entries []AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(audit.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to parse audit entry %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantID     string
+		wantIsUser bool
+	}{
+		{"<@U123456>", "U123456", true},
+		{"@U123456", "U123456", true},
+		{"U123456", "U123456", true},
+		{":sake:", "sake", false},
+		{"sake", "sake", false},
+		{" :beer_mug: ", "beer_mug", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			id, isUser := ParseTarget(tt.input)
+			if id != tt.wantID || isUser != tt.wantIsUser {
+				t.Errorf("ParseTarget(%q) = (%q, %v), want (%q, %v)", tt.input, id, isUser, tt.wantID, tt.wantIsUser)
+			}
+		})
+	}
+}
+
+func TestAdminMutations(t *testing.T) {
+	a, repo, audit, cleanup := setupTestAdmin(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := a.Set(ctx, "<@U111>", 10); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	points, err := a.Adjust(ctx, "U111", -3)
+	if err != nil {
+		t.Fatalf("Adjust() error = %v", err)
+	}
+	if points != 7 {
+		t.Errorf("Adjust() = %v, want 7", points)
+	}
+
+	record, err := a.Get(ctx, "U111")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record.Points != 7 || !record.IsUser {
+		t.Errorf("Get() = %+v, want 7 points for a user", record)
+	}
+
+	if err := a.Reset(ctx, "U111"); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "U111"); points != 0 {
+		t.Errorf("GetPoints() after Reset() = %v, want 0", points)
+	}
+
+	if err := a.Delete(ctx, "U111"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := a.Get(ctx, "U111"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+
+	entries := auditEntries(t, audit)
+	wantActions := []string{"set", "adjust", "reset", "delete"}
+	if len(entries) != len(wantActions) {
+		t.Fatalf("audit log has %d entries, want %d", len(entries), len(wantActions))
+	}
+	for i, action := range wantActions {
+		if entries[i].Action != action || entries[i].Actor != "tester" || entries[i].Target != "U111" {
+			t.Errorf("audit entry %d = %+v, want action %q by tester on U111", i, entries[i], action)
+		}
+	}
+	if entries[1].Before != 10 || entries[1].After != 7 {
+		t.Errorf("adjust audit entry = %+v, want before 10 and after 7", entries[1])
+	}
+}
+
+func TestAdminSetFloored(t *testing.T) {
+	a, repo, audit, cleanup := setupTestAdmin(t)
+	defer cleanup()
+	a.repo = repository.NewPolicyRepository(repo, repository.Policy{AllowMinus: true, FloorAtZero: true})
+
+	ctx := context.Background()
+	points, err := a.Set(ctx, "U111", -5)
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if points != 0 {
+		t.Errorf("Set() = %v, want 0", points)
+	}
+
+	// The audit log records the total as stored, not as requested
+	entries := auditEntries(t, audit)
+	if len(entries) != 1 || entries[0].After != 0 {
+		t.Errorf("audit entries = %+v, want one with after 0", entries)
+	}
+}
+
+func TestAdminHistory(t *testing.T) {
+	a, repo, audit, cleanup := setupTestAdmin(t)
+	defer cleanup()
+	a = a.WithReason("cleanup")
+
+	ctx := context.Background()
+	if _, err := a.Set(ctx, "U111", 10); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := repo.SetPoints(ctx, "sake", 4, false); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	if _, err := a.Merge(ctx, "sake", "U111"); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if err := a.Reset(ctx, "U111"); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if err := a.Delete(ctx, "U111"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	events, err := repo.ListPointEvents(ctx, "", time.Time{})
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	var got []string
+	for _, event := range events {
+		if event.Giver != "tester" || event.Reason != "cleanup" {
+			t.Errorf("event = %+v, want tester as the giver and cleanup as the reason", event)
+		}
+		got = append(got, fmt.Sprintf("%s %d", event.Target, event.Delta))
+	}
+	want := []string{"U111 10", "sake -4", "U111 4", "U111 -14"}
+	if !slices.Equal(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+
+	for _, entry := range auditEntries(t, audit) {
+		if entry.Reason != "cleanup" {
+			t.Errorf("audit entry = %+v, want reason cleanup", entry)
+		}
+	}
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestAdminAuditFailure(t *testing.T) {
+	a, repo, _, cleanup := setupTestAdmin(t)
+	defer cleanup()
+	a.audit = NewAuditLog(failingWriter{})
+
+	ctx := context.Background()
+	_, err := a.Set(ctx, "U111", 10)
+	if err == nil || !strings.Contains(err.Error(), "was applied") {
+		t.Errorf("Set() error = %v, want one saying the change was applied", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "U111"); points != 10 {
+		t.Errorf("GetPoints() = %v, want 10", points)
+	}
+}
+
+func TestAdminRenameAndMerge(t *testing.T) {
+	a, repo, audit, cleanup := setupTestAdmin(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := repo.AddPoints(ctx, "sake", 3, false); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if err := repo.AddPoints(ctx, "beer", 4, false); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+
+	if err := a.Rename(ctx, "sake", "beer"); err == nil {
+		t.Error("Rename() onto an existing target should fail")
+	}
+	if err := a.Rename(ctx, ":sake:", ":nihonshu:"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+
+	total, err := a.Merge(ctx, "nihonshu", "beer")
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if total != 7 {
+		t.Errorf("Merge() = %v, want 7", total)
+	}
+
+	records, err := a.List(ctx, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(records) != 1 || records[0].UserID != "beer" || records[0].Points != 7 || records[0].IsUser {
+		t.Errorf("List() = %+v, want only beer with 7 points", records)
+	}
+
+	if _, err := a.Merge(ctx, "beer", "beer"); err == nil {
+		t.Error("Merge() into itself should fail")
+	}
+
+	entries := auditEntries(t, audit)
+	if len(entries) != 2 {
+		t.Fatalf("audit log has %d entries, want 2", len(entries))
+	}
+	if entries[1].Action != "merge" || entries[1].Source != "nihonshu" || entries[1].Before != 4 || entries[1].After != 7 {
+		t.Errorf("merge audit entry = %+v", entries[1])
+	}
+}
+
+func TestAdminSeasons(t *testing.T) {
+	a, repo, audit, cleanup := setupTestAdmin(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	season, err := a.StartSeason(ctx, "", start)
+	if err != nil {
+		t.Fatalf("StartSeason() error = %v", err)
+	}
+	if season.Name != "2025-04-01" {
+		t.Errorf("StartSeason() name = %q, want the start date", season.Name)
+	}
+	if _, err := a.StartSeason(ctx, "other", start); !errors.Is(err, repository.ErrSeasonInProgress) {
+		t.Errorf("StartSeason() error = %v, want ErrSeasonInProgress", err)
+	}
+
+	if err := repo.AddPointEvent(ctx, repository.PointEvent{Target: "U111", Timestamp: start.Add(time.Hour), Giver: "U222", Delta: 1}); err != nil {
+		t.Fatalf("AddPointEvent() error = %v", err)
+	}
+	season, standings, err := a.EndSeason(ctx, start.AddDate(0, 3, 0))
+	if err != nil {
+		t.Fatalf("EndSeason() error = %v", err)
+	}
+	if season.Name != "2025-04-01" || len(standings) != 1 || standings[0].UserID != "U111" || standings[0].Points != 1 {
+		t.Errorf("EndSeason() = %+v, %+v, want 1 point for U111 in 2025-04-01", season, standings)
+	}
+	if _, _, err := a.EndSeason(ctx, start.AddDate(0, 3, 0)); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("EndSeason() without a season error = %v, want ErrNotFound", err)
+	}
+
+	entries := auditEntries(t, audit)
+	wantActions := []string{"season-start", "season-end"}
+	if len(entries) != len(wantActions) {
+		t.Fatalf("audit log has %d entries, want %d", len(entries), len(wantActions))
+	}
+	for i, action := range wantActions {
+		if entries[i].Action != action || entries[i].Target != "2025-04-01" {
+			t.Errorf("audit entry %d = %+v, want action %q on 2025-04-01", i, entries[i], action)
+		}
+	}
+}
+
+func TestAdminMergeKarma(t *testing.T) {
+	a, repo, audit, cleanup := setupTestAdmin(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := repo.SetPoints(ctx, "U111AAA", 10, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	entries := []transfer.KarmaEntry{
+		{Name: "U111AAA", Points: 5, IsUser: true},
+		{Name: "pizza", Points: 3, IsThing: true},
+	}
+	plan, err := transfer.PlanMerge(ctx, repo, entries, nil)
+	if err != nil {
+		t.Fatalf("PlanMerge() error = %v", err)
+	}
+	if err := a.MergeKarma(ctx, plan); err != nil {
+		t.Fatalf("MergeKarma() error = %v", err)
+	}
+
+	got := auditEntries(t, audit)
+	want := []AuditEntry{
+		{Actor: "tester", Action: "import-karma", Target: "U111AAA", Source: "U111AAA", Before: 10, After: 15},
+		{Actor: "tester", Action: "import-karma", Target: "pizza", Source: "pizza", Before: 0, After: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("audit entries = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		got[i].Time = time.Time{}
+		if got[i] != want[i] {
+			t.Errorf("audit entry %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Merging the same plan again writes and records nothing
+	if err := a.MergeKarma(ctx, plan); err != nil {
+		t.Fatalf("MergeKarma() again error = %v", err)
+	}
+	if entries := auditEntries(t, audit); len(entries) != len(want) {
+		t.Errorf("audit entries after merging again = %d, want %d", len(entries), len(want))
+	}
+}
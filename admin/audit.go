@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntry is a single record of the admin audit log
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Actor  string    `json:"actor"`
+	Action string    `json:"action"`
+	Target string    `json:"target"`
+	Source string    `json:"source,omitempty"`
+	Before int       `json:"before"`
+	After  int       `json:"after"`
+	// Reason is the reason given for the change, if any
+	Reason string `json:"reason,omitempty"`
+}
+
+// AuditLog writes audit entries as JSON Lines
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog creates a new AuditLog writing to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// Record appends an entry to the audit log
+func (l *AuditLog) Record(entry AuditEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// homeListSize is the number of entries in each list of the App Home tab
+const homeListSize = 5
+
+// homeWindow is how far back the App Home tab looks for the points a user received
+const homeWindow = 30 * 24 * time.Hour
+
+// handleAppHomeOpened publishes the Home tab of a user when they open it, and keeps it up to date
+// after their next point changes
+func (b *Bot) handleAppHomeOpened(ev *slackevents.AppHomeOpenedEvent) {
+	b.logger.Debug("Received app home opened event", "event", ev)
+	if ev.Tab != "home" {
+		return
+	}
+	b.homeViewers.Store(ev.User, true)
+	b.publishHome(context.Background(), ev.User)
+}
+
+// refreshHomes republishes the Home tab of the given users who have opened it, in the background
+func (b *Bot) refreshHomes(users ...string) {
+	for _, user := range users {
+		if _, ok := b.homeViewers.Load(user); ok && user != "" {
+			b.homeRefreshes.Add(1)
+			go func() {
+				defer b.homeRefreshes.Done()
+				b.publishHome(context.Background(), user)
+			}()
+		}
+	}
+}
+
+// publishHome publishes the Home tab of a user
+func (b *Bot) publishHome(ctx context.Context, user string) {
+	blocks, err := b.homeBlocks(ctx, user)
+	if err != nil {
+		b.logger.Error("Error building home tab", "user", user, "error", err)
+		return
+	}
+	view := slack.HomeTabViewRequest{Type: slack.VTHomeTab, Blocks: slack.Blocks{BlockSet: blocks}}
+	if _, err := b.api.PublishView(user, view, ""); err != nil {
+		b.logger.Error("Error publishing home tab", "user", user, "error", err)
+		return
+	}
+	b.logger.Debug("Home tab published", "user", user)
+}
+
+// homeBlocks creates the Home tab of a user: their stats, the points they received recently,
+// who they received the most points from in the homeWindow, who they give the most points to, and the leaderboard
+func (b *Bot) homeBlocks(ctx context.Context, user string) ([]slack.Block, error) {
+	locale := b.localeFor("", user)
+	messages := (*catalog.Load()).Messages(locale)
+	stats, err := b.statsData(ctx, messages, user)
+	if err != nil {
+		return nil, err
+	}
+	events, err := b.repo.ListPointEvents(ctx, user, time.Now().Add(-homeWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list point events: %w", err)
+	}
+	recipients, err := b.repo.ListGivenPoints(ctx, user, homeListSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list given points: %w", err)
+	}
+	records, err := b.rankedPoints(ctx, defaultLeaderboardSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list points: %w", err)
+	}
+
+	var recent []string
+	givers := map[string]int{}
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if event.Delta <= 0 {
+			continue
+		}
+		givers[event.Giver] += event.Delta
+		if len(recent) < homeListSize {
+			data := newMessageData(user, true, 0).withGiver(event.Giver)
+			data.pointsString = messages.formatPoints
+			data.Delta, data.Reason, data.Received = event.Delta, event.Reason, slackDate(event.Timestamp)
+			recent = append(recent, renderText(messages.HomeRecentLine, data))
+		}
+	}
+	recentText := messages.HomeRecentEmpty
+	if len(recent) > 0 {
+		recentText = "*" + messages.HomeRecentTitle + "*"
+		for _, line := range recent {
+			recentText += "\n" + line
+		}
+	}
+
+	leaderboardTitle := messages.LeaderboardTitle
+	if b.decay.enabled() {
+		leaderboardTitle = messages.LeaderboardDecayedTitle
+	}
+	return []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, messages.HomeTitle, false, false)),
+		markdownSection(renderText(messages.Stats, stats)),
+		slack.NewDividerBlock(),
+		markdownSection(recentText),
+		slack.NewDividerBlock(),
+		markdownSection(rankingReply(messages, messages.HomeGiversTitle, messages.HomeGiversEmpty, topGivers(givers)).text),
+		markdownSection(rankingReply(messages, messages.HomeRecipientsTitle, messages.HomeRecipientsEmpty, recipients).text),
+		slack.NewDividerBlock(),
+		markdownSection(rankingReply(messages, leaderboardTitle, messages.LeaderboardEmpty, records).text),
+	}, nil
+}
+
+// topGivers returns the givers who gave the most points, highest first, up to homeListSize
+func topGivers(points map[string]int) []repository.UserPoints {
+	records := make([]repository.UserPoints, 0, len(points))
+	for giver, total := range points {
+		records = append(records, repository.UserPoints{UserID: giver, Points: total, IsUser: true})
+	}
+	slices.SortFunc(records, func(a, b repository.UserPoints) int {
+		return cmp.Or(cmp.Compare(b.Points, a.Points), cmp.Compare(a.UserID, b.UserID))
+	})
+	return records[:min(len(records), homeListSize)]
+}
+
+// markdownSection returns a section block with a markdown text
+func markdownSection(text string) *slack.SectionBlock {
+	return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)
+}
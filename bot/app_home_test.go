@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestAppHome(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	// home returns the Home tab published for a user in the output, if any
+	home := func(user string) string {
+		t.Helper()
+		_, tab, ok := strings.Cut(out.String(), "@"+user+" [home]: ")
+		if !ok {
+			return ""
+		}
+		return tab
+	}
+
+	sim.bot.handleAppHomeOpened(&slackevents.AppHomeOpenedEvent{User: "U1", Tab: "messages"})
+	if got := out.String(); got != "" {
+		t.Errorf("messages tab = %q, want nothing published", got)
+	}
+
+	sim.bot.handleAppHomeOpened(&slackevents.AppHomeOpenedEvent{User: "U1", Tab: "home"})
+	want := "Your ++ dashboard\n" +
+		"<@U1> has 0 points and has given 0 points to 0 targets.\n" +
+		"Nobody has given you points recently.\n" +
+		"Nobody has given you points in the last 30 days.\n" +
+		"You haven't given anyone points yet.\n" +
+		"Nobody has any points yet.\n"
+	if got := home("U1"); got != want {
+		t.Errorf("empty home = %q, want %q", got, want)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U2 #general: for the review <@U1>++"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if err := sim.HandleLine("U1 #general: :sake:++"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if got := home("U2"); got != "" {
+		t.Errorf("home of U2 = %q, want nothing published before U2 opens it", got)
+	}
+
+	out.Reset()
+	sim.bot.handleAppHomeOpened(&slackevents.AppHomeOpenedEvent{User: "U1", Tab: "home"})
+	want = "Your ++ dashboard\n" +
+		"<@U1> has 1 point (#1) and has given 1 point to 1 target, most recently " + slackDate(time.Now()) + ". " +
+		"Giving streak: 1 day (longest 1).\n" +
+		"*Recent points you received*\n• <@U2> gave you 1 point " + slackDate(time.Now()) + ": for the review\n" +
+		"*Who gave you the most in the last 30 days*\n1. <@U2>: 1 point\n" +
+		"*Who you give the most*\n1. :sake:: 1 point\n" +
+		"*Leaderboard*\n1. <@U1>: 1 point\n1. :sake:: 1 point\n"
+	if got := home("U1"); got != want {
+		t.Errorf("home = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"plusplusbot/infra/repository"
+)
+
+// BadgeRule is what a user must achieve to earn a badge
+type BadgeRule string
+
+const (
+	// BadgeReceived is earned by receiving at least Threshold points in total
+	BadgeReceived BadgeRule = "received"
+	// BadgePoints is earned by reaching a total of at least Threshold points
+	BadgePoints BadgeRule = "points"
+	// BadgeGivers is earned by receiving points from at least Threshold different users
+	BadgeGivers BadgeRule = "givers"
+	// BadgeGiven is earned by giving at least Threshold points in total
+	BadgeGiven BadgeRule = "given"
+	// BadgeRecipients is earned by giving points to at least Threshold different targets
+	BadgeRecipients BadgeRule = "recipients"
+	// BadgeGivingStreak is earned by giving points on at least Threshold consecutive days
+	BadgeGivingStreak BadgeRule = "giving_streak"
+)
+
+// forGiver reports whether the rule is about the points a user gives rather than receives
+func (r BadgeRule) forGiver() bool {
+	return r == BadgeGiven || r == BadgeRecipients || r == BadgeGivingStreak
+}
+
+// Badge is an achievement awarded to users once they meet its rule
+type Badge struct {
+	// ID identifies the badge in storage; renaming a badge keeps the awards as long as its ID stays
+	ID          string
+	Name        string
+	Emoji       string
+	Description string
+	Rule        BadgeRule
+	Threshold   int
+}
+
+// title returns the emoji and the name of the badge, like ":seedling: First ++"
+func (badge Badge) title() string {
+	if badge.Emoji == "" {
+		return badge.Name
+	}
+	return badge.Emoji + " " + badge.Name
+}
+
+// badgeProgress computes the counters badge rules are checked against, loading each of them once
+type badgeProgress struct {
+	b    *Bot
+	ctx  context.Context
+	user string
+
+	received, givers *int
+	stats            *repository.GiverStats
+	points, streak   *int
+}
+
+// value returns the counter of a rule for the user
+func (p *badgeProgress) value(rule BadgeRule) (int, error) {
+	switch rule {
+	case BadgeReceived, BadgeGivers:
+		if p.received == nil {
+			events, err := p.b.repo.ListPointEvents(p.ctx, p.user, time.Time{})
+			if err != nil {
+				return 0, err
+			}
+			received, givers := 0, map[string]bool{}
+			for _, event := range events {
+				if event.Delta > 0 {
+					received += event.Delta
+					givers[event.Giver] = true
+				}
+			}
+			count := len(givers)
+			p.received, p.givers = &received, &count
+		}
+		if rule == BadgeReceived {
+			return *p.received, nil
+		}
+		return *p.givers, nil
+	case BadgePoints:
+		if p.points == nil {
+			points, err := p.b.repo.GetPoints(p.ctx, p.user)
+			if err != nil {
+				return 0, err
+			}
+			p.points = &points
+		}
+		return *p.points, nil
+	case BadgeGiven, BadgeRecipients:
+		if p.stats == nil {
+			stats, err := p.b.repo.GetGiverStats(p.ctx, p.user)
+			if err != nil {
+				return 0, err
+			}
+			p.stats = stats
+		}
+		if rule == BadgeGiven {
+			return p.stats.Given, nil
+		}
+		return p.stats.Recipients, nil
+	case BadgeGivingStreak:
+		if p.streak == nil {
+			streak, err := p.b.repo.GetGivingStreak(p.ctx, p.user)
+			if err != nil {
+				return 0, err
+			}
+			current := p.b.currentStreak(*streak, time.Now())
+			p.streak = &current
+		}
+		return *p.streak, nil
+	default:
+		return 0, fmt.Errorf("unknown badge rule: %s", rule)
+	}
+}
+
+// awardBadges awards a user the badges of the given side (giving or receiving) whose rules they now meet,
+// and returns the badges that are new
+func (b *Bot) awardBadges(ctx context.Context, user string, giver bool) ([]Badge, error) {
+	if len(b.badges) == 0 {
+		return nil, nil
+	}
+	awarded, err := b.repo.ListBadges(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	owned := map[string]bool{}
+	for _, badge := range awarded {
+		owned[badge.Badge] = true
+	}
+
+	progress := &badgeProgress{b: b, ctx: ctx, user: user}
+	var earned []Badge
+	for _, badge := range b.badges {
+		if owned[badge.ID] || badge.Rule.forGiver() != giver {
+			continue
+		}
+		value, err := progress.value(badge.Rule)
+		if err != nil {
+			return earned, err
+		}
+		if value < badge.Threshold {
+			continue
+		}
+		added, err := b.repo.AddBadge(ctx, repository.UserBadge{UserID: user, Badge: badge.ID, AwardedAt: time.Now()})
+		if err != nil {
+			return earned, err
+		}
+		if added {
+			b.logger.Info("Badge awarded", "user", user, "badge", badge.ID)
+			earned = append(earned, badge)
+		}
+	}
+	return earned, nil
+}
+
+// badgeLines awards the badges earned by a point change to the giver and to the recipient, if it is a user,
+// and returns the announcements to add to the reply
+func (b *Bot) badgeLines(ctx context.Context, locale, giver, recipient string) []string {
+	messages := (*catalog.Load()).Messages(locale)
+	var lines []string
+	for _, award := range []struct {
+		user  string
+		giver bool
+	}{{recipient, false}, {giver, true}} {
+		if award.user == "" {
+			continue
+		}
+		earned, err := b.awardBadges(ctx, award.user, award.giver)
+		if err != nil {
+			b.logger.Error("Error awarding badges", "user", award.user, "error", err)
+		}
+		for _, badge := range earned {
+			data := newMessageData(award.user, true, 0)
+			data.Badge, data.BadgeDescription = badge.title(), badge.Description
+			lines = append(lines, renderText(messages.BadgeAwarded, data))
+		}
+	}
+	return lines
+}
+
+// handleBadgesCommand replies with the badges a user has earned: "badges [@user]".
+// Without an argument, it replies with the badges of the sender.
+func (b *Bot) handleBadgesCommand(cmd command) {
+	messages := (*catalog.Load()).Messages(b.localeFor(cmd.channel, cmd.user))
+	user := cmd.user
+	if len(cmd.args) > 0 {
+		matches := mentionPattern.FindStringSubmatch(cmd.args[0])
+		if matches == nil || len(cmd.args) > 1 {
+			b.postReply(cmd.channel, cmd.threadTS, reply{text: messages.BadgesUsage})
+			return
+		}
+		user = matches[1]
+	}
+
+	awarded, err := b.repo.ListBadges(context.Background(), user)
+	if err != nil {
+		b.logger.Error("Error listing badges", "error", err)
+		return
+	}
+
+	data := newMessageData(user, true, 0)
+	if len(awarded) == 0 {
+		b.postReply(cmd.channel, cmd.threadTS, reply{text: renderText(messages.BadgesEmpty, data)})
+		return
+	}
+	badges := map[string]Badge{}
+	for _, badge := range b.badges {
+		badges[badge.ID] = badge
+	}
+	text := "*" + renderText(messages.BadgesTitle, data) + "*"
+	for _, award := range awarded {
+		// Badges removed from the configuration are shown by their ID
+		badge, ok := badges[award.Badge]
+		if !ok {
+			badge = Badge{ID: award.Badge, Name: award.Badge}
+		}
+		line := data
+		line.Badge, line.BadgeDescription = badge.title(), badge.Description
+		line.Awarded = slackDate(award.AwardedAt)
+		text += "\n" + renderText(messages.BadgesLine, line)
+	}
+	b.postReply(cmd.channel, cmd.threadTS, reply{text: text})
+}
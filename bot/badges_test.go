@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"plusplusbot/infra/repository"
+)
+
+func TestAwardBadges(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithBadges([]Badge{
+		{ID: "first-plus", Name: "First ++", Emoji: ":seedling:", Description: "Received a first point", Rule: BadgeReceived, Threshold: 1},
+		{ID: "two-givers", Name: "Crowd favorite", Rule: BadgeGivers, Threshold: 2},
+		{ID: "three-club", Name: "Three club", Rule: BadgePoints, Threshold: 3},
+		{ID: "generous", Name: "Generous", Rule: BadgeGiven, Threshold: 2},
+		{ID: "streak", Name: "On a roll", Rule: BadgeGivingStreak, Threshold: 2},
+	})(sim.bot)
+
+	// U1 gave points yesterday, so giving today makes a streak of two days
+	ctx := context.Background()
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+	if err := sim.bot.repo.PutGivingStreak(ctx, repository.GivingStreak{UserID: "U1", Current: 1, Longest: 1, LastDay: yesterday}); err != nil {
+		t.Fatalf("PutGivingStreak() error = %v", err)
+	}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"U1 #general: <@U2>++", []string{
+			":medal: <@U2> earned the :seedling: First ++ badge!",
+			":medal: <@U1> earned the On a roll badge!",
+		}},
+		{"U1 #general: <@U2>++", []string{
+			":medal: <@U1> earned the Generous badge!",
+		}},
+		{"U3 #general: <@U2>++", []string{
+			":medal: <@U2> earned the Crowd favorite badge!",
+			":medal: <@U2> earned the Three club badge!",
+		}},
+		// Badges are awarded once
+		{"U3 #general: <@U2>++", []string{
+			":medal: <@U3> earned the Generous badge!",
+		}},
+		// Emoji do not earn badges
+		{"U4 #general: :sake:++", nil},
+	}
+	for _, tt := range tests {
+		out.Reset()
+		if err := sim.HandleLine(tt.line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+		var got []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if strings.HasPrefix(line, ":medal:") {
+				got = append(got, line)
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: badges = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U1 #general: <@UBOT> badges <@U2>"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, "#general: *Badges of <@U2>*\n• :seedling: First ++ – Received a first point (<!date^") ||
+		!strings.Contains(got, "• Crowd favorite (") || !strings.Contains(got, "• Three club (") {
+		t.Errorf("badges = %q", got)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U4 #general: <@UBOT> badges"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if got := out.String(); got != "#general: <@U4> has no badges yet.\n" {
+		t.Errorf("badges of the sender = %q", got)
+	}
+}
@@ -2,11 +2,15 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
 	"plusplusbot/infra/repository"
 	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
@@ -23,17 +27,242 @@ func (l *logAdapter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// SlackAPI is the subset of the Slack Web API used by the bot
+type SlackAPI interface {
+	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
+	GetUserInfo(user string) (*slack.User, error)
+	AddReaction(name string, item slack.ItemRef) error
+	GetPermalink(params *slack.PermalinkParameters) (string, error)
+	GetUserGroupMembers(userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error)
+	PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error)
+	OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
+}
+
 // Bot represents a Slack bot instance
 type Bot struct {
-	api          *slack.Client
+	api          SlackAPI
 	socketClient *socketmode.Client
 	verbose      bool
 	logger       *slog.Logger
 	repo         repository.UserPointsRepository
+
+	// locale is the workspace-wide locale of replies
+	locale string
+	// channelLocales overrides the locale of replies per channel ID
+	channelLocales map[string]string
+	// useUserLocale enables replying in the Slack locale of the user who sent the message
+	useUserLocale bool
+	// richReplies enables Block Kit replies with a plain text fallback
+	richReplies bool
+	// milestones and shameMilestones are the totals celebrated when a target reaches them
+	milestones      []int
+	shameMilestones []int
+	// milestoneChannel is the channel milestones are also announced in, if set
+	milestoneChannel string
+	// replyMode is how the bot responds to point changes
+	replyMode ReplyMode
+	// channelReplyModes overrides the reply mode per channel ID
+	channelReplyModes map[string]ReplyMode
+	// notificationDelay is how long point changes are collected before notifying their recipient
+	notificationDelay time.Duration
+	// notifications batches direct message notifications to recipients
+	notifications *notifier
+	// channelAllowlist makes the bot respond only in channels enabled with the channel command
+	channelAllowlist bool
+	// botManagers are the users allowed to change channel settings besides workspace admins
+	botManagers []string
+	// allowMinus is whether -- is allowed in channels without a setting; the repository enforces it
+	allowMinus bool
+	// selfVotePolicy is what happens when someone gives points to themselves or to a target they own
+	selfVotePolicy SelfVotePolicy
+	// targetOwners are the users owning each non-user target, such as a personal emoji
+	targetOwners map[string][]string
+	// collusion configures the detection of users trading points back and forth
+	collusion CollusionDetection
+	// location is the time zone period boundaries are computed in; nil means UTC
+	location *time.Location
+	// digests are the summaries posted on a schedule
+	digests []Digest
+	// badges are awarded to users who meet their rules after a point change
+	badges []Badge
+	// streakNudges remind users of giving streaks about to break
+	streakNudges StreakNudges
+	// decay makes old points count less in rankings
+	decay Decay
+	// decayed caches the decayed scores rankings are read from when decay is enabled
+	decayed decayedScores
+	// seasons end and start on the season schedule and are announced in the season channel
+	seasons Seasons
+	// homeViewers are the IDs of the users who have opened the App Home tab, whose tab is refreshed
+	// after their point changes
+	homeViewers sync.Map
+	// homeRefreshes tracks the Home tabs being republished in the background
+	homeRefreshes sync.WaitGroup
+}
+
+// Option configures optional behavior of the bot
+type Option func(*Bot)
+
+// WithLocale sets the workspace-wide locale of replies
+func WithLocale(locale string) Option {
+	return func(b *Bot) {
+		if locale != "" {
+			b.locale = locale
+		}
+	}
+}
+
+// WithChannelLocales sets the locale of replies per channel ID
+func WithChannelLocales(locales map[string]string) Option {
+	return func(b *Bot) {
+		b.channelLocales = locales
+	}
+}
+
+// WithUserLocale makes the bot reply in the Slack locale of the user who sent the message,
+// unless a locale is set for the channel
+func WithUserLocale(enabled bool) Option {
+	return func(b *Bot) {
+		b.useUserLocale = enabled
+	}
+}
+
+// WithRichReplies makes the bot reply with Block Kit messages, keeping the plain text as a fallback
+func WithRichReplies(enabled bool) Option {
+	return func(b *Bot) {
+		b.richReplies = enabled
+	}
+}
+
+// WithMilestones sets the totals celebrated when a target reaches them, and the negative totals
+// announced when a target sinks to them
+func WithMilestones(milestones, shameMilestones []int) Option {
+	return func(b *Bot) {
+		b.milestones = milestones
+		b.shameMilestones = shameMilestones
+	}
+}
+
+// WithMilestoneChannel sets the channel milestones are also announced in
+func WithMilestoneChannel(channel string) Option {
+	return func(b *Bot) {
+		b.milestoneChannel = channel
+	}
+}
+
+// WithReplyMode sets how the bot responds to point changes; empty values are ignored
+func WithReplyMode(mode ReplyMode) Option {
+	return func(b *Bot) {
+		if mode != "" {
+			b.replyMode = mode
+		}
+	}
+}
+
+// WithChannelReplyModes sets how the bot responds to point changes per channel ID
+func WithChannelReplyModes(modes map[string]ReplyMode) Option {
+	return func(b *Bot) {
+		b.channelReplyModes = modes
+	}
+}
+
+// WithNotificationDelay sets how long point changes are collected before notifying their recipient
+// in one direct message; zero sends a message for every change
+func WithNotificationDelay(delay time.Duration) Option {
+	return func(b *Bot) {
+		b.notificationDelay = delay
+	}
+}
+
+// WithChannelAllowlist makes the bot respond to point operations only in channels enabled with
+// "@plusplusbot channel enabled on"; otherwise it responds in every channel not disabled
+func WithChannelAllowlist(enabled bool) Option {
+	return func(b *Bot) {
+		b.channelAllowlist = enabled
+	}
+}
+
+// WithBotManagers sets the users allowed to change channel settings besides workspace admins and owners
+func WithBotManagers(users []string) Option {
+	return func(b *Bot) {
+		b.botManagers = users
+	}
+}
+
+// WithAllowMinus sets whether -- is allowed in channels without a setting. It is only used to describe
+// channel settings; the repository enforces the policy.
+func WithAllowMinus(allowed bool) Option {
+	return func(b *Bot) {
+		b.allowMinus = allowed
+	}
+}
+
+// WithSelfVotePolicy sets what happens when someone gives points to themselves or to a target they own
+func WithSelfVotePolicy(policy SelfVotePolicy) Option {
+	return func(b *Bot) {
+		b.selfVotePolicy = policy
+	}
+}
+
+// WithTargetOwners sets the users owning each non-user target, such as a personal emoji,
+// who cannot give points to it
+func WithTargetOwners(owners map[string][]string) Option {
+	return func(b *Bot) {
+		b.targetOwners = owners
+	}
+}
+
+// WithCollusionDetection sets how users trading points back and forth are detected and handled
+func WithCollusionDetection(collusion CollusionDetection) Option {
+	return func(b *Bot) {
+		b.collusion = collusion
+	}
+}
+
+// WithTimezone sets the time zone period boundaries, such as the start of a week, are computed in
+func WithTimezone(location *time.Location) Option {
+	return func(b *Bot) {
+		b.location = location
+	}
+}
+
+// WithDigests sets the summaries of the points given in a period posted on a schedule
+func WithDigests(digests []Digest) Option {
+	return func(b *Bot) {
+		b.digests = digests
+	}
+}
+
+// WithBadges sets the badges users earn
+func WithBadges(badges []Badge) Option {
+	return func(b *Bot) {
+		b.badges = badges
+	}
+}
+
+// WithStreakNudges sets the schedule users are reminded of giving streaks about to break on
+func WithStreakNudges(nudges StreakNudges) Option {
+	return func(b *Bot) {
+		b.streakNudges = nudges
+	}
+}
+
+// WithDecay sets how old points lose their weight in rankings
+func WithDecay(decay Decay) Option {
+	return func(b *Bot) {
+		b.decay = decay
+	}
+}
+
+// WithSeasons sets the schedule seasons are rolled over on and the channel they are announced in
+func WithSeasons(seasons Seasons) Option {
+	return func(b *Bot) {
+		b.seasons = seasons
+	}
 }
 
 // New creates a new Slack bot instance
-func New(botToken, appToken string, repo repository.UserPointsRepository, verbose bool, logger *slog.Logger) (*Bot, error) {
+func New(botToken, appToken string, repo repository.UserPointsRepository, verbose bool, logger *slog.Logger, opts ...Option) (*Bot, error) {
 	if botToken == "" || appToken == "" {
 		return nil, fmt.Errorf("SLACK_BOT_TOKEN or SLACK_APP_TOKEN is not set")
 	}
@@ -53,19 +282,31 @@ func New(botToken, appToken string, repo repository.UserPointsRepository, verbos
 		socketmode.OptionLog(socketLogger),
 	)
 
-	return &Bot{
+	b := &Bot{
 		api:          api,
 		socketClient: socketClient,
 		verbose:      verbose,
 		logger:       logger,
 		repo:         repo,
-	}, nil
+		locale:       DefaultLocale,
+		replyMode:    ReplyInChannel,
+		allowMinus:   true,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.notifications = newNotifier(b.notificationDelay, true, b.sendNotifications)
+
+	return b, nil
 }
 
 // Start starts the Slack bot
 func (b *Bot) Start() {
 	b.logger.Debug("Starting bot(version: " + Version + ")...")
 	go b.handleEvents()
+	b.startDigests()
+	b.startSeasons()
+	b.startStreakNudges()
 	b.logger.Debug("Starting socket mode client...")
 	if err := b.socketClient.Run(); err != nil {
 		b.logger.Error("Error running socket client", "error", err)
@@ -116,6 +357,64 @@ func detectOperationAndTarget(text string) (PointOperation, string, bool) {
 	return NoOperation, "", false
 }
 
+// extractReason returns the text of a message without its point operation, such as
+// "thanks for the review" for "thanks for the review <@U123456> ++". The operator ends its line,
+// so a reason comes before the target or on the following lines.
+func extractReason(text string, isUser bool) string {
+	patterns := []*regexp.Regexp{emojiOperationPattern}
+	if isUser {
+		patterns = []*regexp.Regexp{userOperationPattern, userGroupOperationPattern}
+	}
+	for _, pattern := range patterns {
+		if loc := pattern.FindStringIndex(text); loc != nil {
+			return strings.Join(strings.Fields(text[:loc[0]]+" "+text[loc[1]:]), " ")
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+// messageData creates the template data for a reply to a message about target
+func (b *Bot) messageData(ev *slackevents.MessageEvent, target string, isUser bool, points, delta int) MessageData {
+	data := newMessageData(target, isUser, points).withGiver(ev.User)
+	data.Delta = delta
+	data.Previous = points - delta
+	data.Reason = extractReason(ev.Text, isUser)
+	data.loadRank = func() int {
+		rank, err := b.rank(context.Background(), target)
+		if err != nil {
+			b.logger.Error("Error getting rank", "error", err)
+		}
+		return rank
+	}
+	return data
+}
+
+// pointEvent creates the point history entry of a point change made by a message
+func pointEvent(ev *slackevents.MessageEvent, target string, isUser bool, delta int, dampened bool) repository.PointEvent {
+	return repository.PointEvent{
+		Target:    target,
+		Timestamp: time.Now(),
+		Giver:     ev.User,
+		Channel:   ev.Channel,
+		Delta:     delta,
+		Dampened:  dampened,
+		Reason:    extractReason(ev.Text, isUser),
+	}
+}
+
+// recordGiving adds the points given in a recorded point change to the counters and the giving streak
+// of the giver
+func (b *Bot) recordGiving(ctx context.Context, event repository.PointEvent) {
+	if event.Delta > 0 {
+		if err := b.repo.AddGivenPoints(ctx, event.Giver, event.Target, event.Delta); err != nil {
+			b.logger.Error("Error counting given points", "error", err)
+		}
+	}
+	if countsAsGiving(event) {
+		b.updateGivingStreak(ctx, event.Giver, event.Timestamp)
+	}
+}
+
 // detectPointOperation checks if the message contains a point operation (++, --, ==)
 func (b *Bot) detectPointOperation(text string) PointOperation {
 	op, _, _ := detectOperationAndTarget(text)
@@ -132,19 +431,75 @@ func (b *Bot) isUser(userID string) (bool, error) {
 	return !user.IsBot, nil
 }
 
-// handlePointChangeMessage processes a point up or down message
-func (b *Bot) handlePointChangeMessage(ev *slackevents.MessageEvent, operation PointOperation, target string, isUser bool) {
+// isWorkspaceAdmin checks if the given user ID belongs to a workspace admin or owner
+func (b *Bot) isWorkspaceAdmin(userID string) bool {
+	info, err := b.api.GetUserInfo(userID)
+	if err != nil {
+		b.logger.Error("Error getting user info", "error", err)
+		return false
+	}
+	return info.IsAdmin || info.IsOwner || info.IsPrimaryOwner
+}
 
-	// Check if user is trying to point themselves (only applies to user targets)
-	if isUser && target == ev.User {
-		message := getFormattedMessage(SelfMessage, target, 0, true)
-		_, _, err := b.api.PostMessage(ev.Channel, slack.MsgOptionText(message, false), slack.MsgOptionTS(ev.ThreadTimeStamp))
+// localeFor returns the locale to reply in to a message sent by user in channel
+func (b *Bot) localeFor(channel, user string) string {
+	if channel != "" {
+		if locale := b.channelSettings(channel).Locale; locale != "" {
+			return locale
+		}
+	}
+	if locale, ok := b.channelLocales[channel]; ok {
+		return locale
+	}
+	if b.useUserLocale && user != "" {
+		info, err := b.api.GetUserInfo(user)
 		if err != nil {
-			b.logger.Error("Error sending message", "error", err)
+			b.logger.Error("Error getting user locale", "error", err)
+		} else if info.Locale != "" {
+			return info.Locale
 		}
+	}
+	return b.locale
+}
+
+// handlePointChangeMessage processes a point up or down message
+func (b *Bot) handlePointChangeMessage(ev *slackevents.MessageEvent, operation PointOperation, target string, isUser bool) {
+	b.changePoints(ev, operation, target, isUser, 1, "")
+}
+
+// pointChangeResponse is the response to a point change that has been applied
+type pointChangeResponse struct {
+	messageType MessageType
+	// recipient is the user the reply is sent to in the silent reply mode, if any
+	recipient string
+	reply     reply
+	// after announces the milestone reached with the change, if any, and refreshes the App Home of the users
+	// involved. It is called after the reply.
+	after func()
+}
+
+// changePoints gives or takes amount points to or from a target and responds to the change.
+// The permalink, if set, is the message the points are given for, and is linked in the reply.
+func (b *Bot) changePoints(ev *slackevents.MessageEvent, operation PointOperation, target string, isUser bool, amount int, permalink string) {
+	// Check if user is trying to point themselves or a target they own
+	if b.isSelfVote(ev.User, target, isUser) {
+		b.handleSelfVote(ev, b.localeFor(ev.Channel, ev.User), target, isUser)
 		return
 	}
 
+	response, ok := b.applyPointChange(ev, operation, target, isUser, amount, permalink)
+	if !ok {
+		return
+	}
+	b.respond(ev, response.messageType, response.recipient, response.reply)
+	response.after()
+}
+
+// applyPointChange gives or takes amount points to or from a target, which must not be a self vote, and returns
+// the response to send. It reports false if nothing was changed and there is nothing to respond.
+func (b *Bot) applyPointChange(ev *slackevents.MessageEvent, operation PointOperation, target string, isUser bool, amount int, permalink string) (pointChangeResponse, bool) {
+	locale := b.localeFor(ev.Channel, ev.User)
+
 	// For user targets, check if they are bots
 	is_user_target := false
 	if isUser {
@@ -152,48 +507,101 @@ func (b *Bot) handlePointChangeMessage(ev *slackevents.MessageEvent, operation P
 		is_user_target, err = b.isUser(target)
 		if err != nil {
 			b.logger.Error("Error checking if user is bot", "error", err)
-			return
+			return pointChangeResponse{}, false
 		}
 	} else {
 		// For emoji targets, treat as non-user (similar to bot behavior)
 		is_user_target = false
 	}
 
-	pointsChange := 1
+	pointsChange := amount
 	if operation == PointDown {
-		pointsChange = -1
-	}
-
-	// Add points to the target
-	ctx := context.Background()
-	if err := b.repo.AddPoints(ctx, target, pointsChange, is_user_target); err != nil {
-		b.logger.Error("Error adding points", "error", err)
-		return
+		pointsChange = -amount
 	}
 
-	// Get current points
-	points, err := b.repo.GetPoints(ctx, target)
+	// Add points to the target; the repository applies the point policy of the channel
+	ctx := repository.WithChannel(context.Background(), ev.Channel)
+	previous, err := b.repo.GetPoints(ctx, target)
 	if err != nil {
 		b.logger.Error("Error getting points", "error", err)
-		return
+		return pointChangeResponse{}, false
 	}
 
-	// Send messages
-	var message string
-	switch operation {
-	case PointDown:
-		message = getFormattedMessage(MinusPointsMessage, target, points, isUser)
-	case PointUp:
-		message = getFormattedMessage(PlusPointsMessage, target, points, isUser)
+	// Check if the giver and the target trade points back and forth
+	dampened := false
+	if operation == PointUp && is_user_target && b.collusion.Threshold > 0 {
+		pair, err := b.checkReciprocal(ctx, ev.User, target, time.Now())
+		if err != nil {
+			b.logger.Error("Error checking reciprocal giving", "error", err)
+		} else if pair.flagged(b.collusion.Threshold) {
+			b.logger.Warn("Reciprocal giving detected", "giver", ev.User, "target", target, "given", pair.given, "returned", pair.returned)
+			if pair.given == b.collusion.Threshold {
+				b.reportReciprocalPair(pair)
+			}
+			dampened = b.collusion.Dampen
+		}
 	}
 
-	_, _, err = b.api.PostMessage(ev.Channel, slack.MsgOptionText(message, false), slack.MsgOptionTS(ev.ThreadTimeStamp))
+	// Add the points and record the change in the history together; dampened changes are only recorded
+	event := pointEvent(ev, target, isUser, pointsChange, dampened)
+	change := repository.PointChange{Points: previous}
+	if dampened {
+		event.Delta = 0
+		err = b.repo.AddPointEvent(ctx, event)
+	} else {
+		change, err = b.repo.AddPointsWithEvent(ctx, event, is_user_target)
+	}
 	if err != nil {
-		b.logger.Error("Error sending message", "error", err)
-		return
+		if errors.Is(err, repository.ErrMinusDisabled) {
+			b.logger.Info("Negative points are disabled", "channel", ev.Channel)
+			data := b.messageData(ev, target, isUser, previous, 0)
+			return pointChangeResponse{
+				messageType: MinusDisabledMessage,
+				reply:       pointsReply(locale, MinusDisabledMessage, data),
+				after:       func() {},
+			}, true
+		}
+		b.logger.Error("Error adding points", "error", err)
+		return pointChangeResponse{}, false
 	}
 
-	b.logger.Debug("Reply sent", "message", message)
+	// The change can be smaller than requested when totals are floored at zero, or none when dampened.
+	// The repository returns it as stored, so that concurrent changes to the target don't skew it.
+	points := change.Points
+	pointsChange = change.Delta
+	event.Delta = pointsChange
+	b.recordGiving(ctx, event)
+
+	// Send messages
+	messageType := PlusPointsMessage
+	if operation == PointDown {
+		messageType = MinusPointsMessage
+	}
+	data := b.messageData(ev, target, isUser, points, pointsChange)
+	data.Permalink = permalink
+	recipient := ""
+	if is_user_target {
+		recipient = target
+	}
+	// In the silent reply mode, the notification takes the place of the direct reply
+	replyRecipient := recipient
+	if recipient != "" && b.notifyRecipient(ev, recipient, pointsChange, data.Reason) {
+		replyRecipient = ""
+	}
+	var lines []string
+	if permalink != "" {
+		lines = append(lines, renderText((*catalog.Load()).Messages(locale).KudosLink, data))
+	}
+	r := pointsReply(locale, messageType, data).withLines(append(lines, b.badgeLines(ctx, locale, ev.User, recipient)...))
+	return pointChangeResponse{
+		messageType: messageType,
+		recipient:   replyRecipient,
+		reply:       r,
+		after: func() {
+			b.celebrateMilestone(ev, locale, recipient, data)
+			b.refreshHomes(ev.User, recipient)
+		},
+	}, true
 }
 
 func (b *Bot) handlePointCheckMessage(ev *slackevents.MessageEvent, target string, isUser bool) {
@@ -204,23 +612,31 @@ func (b *Bot) handlePointCheckMessage(ev *slackevents.MessageEvent, target strin
 		return
 	}
 
-	message := getFormattedMessage(EqualsMessage, target, points, isUser)
-
-	_, _, err = b.api.PostMessage(ev.Channel, slack.MsgOptionText(message, false), slack.MsgOptionTS(ev.ThreadTimeStamp))
-	if err != nil {
-		b.logger.Error("Error sending message", "error", err)
-	}
+	data := b.messageData(ev, target, isUser, points, 0)
+	b.postReply(ev.Channel, ev.ThreadTimeStamp, pointsReply(b.localeFor(ev.Channel, ev.User), EqualsMessage, data))
 }
 
 // handleMessageEvent processes a message event
 func (b *Bot) handleMessageEvent(ev *slackevents.MessageEvent) {
 	b.logger.Debug("Received message event", "event", ev)
 	operation, target, isUser := detectOperationAndTarget(ev.Text)
-	if operation != NoOperation && target != "" {
-		b.logger.Info("Point operation detected", "text", ev.Text, "target", target, "isUser", isUser)
-		if operation == PointCheck {
+	group := ""
+	if operation == NoOperation {
+		operation, group = detectUserGroupOperation(ev.Text)
+	}
+	if operation != NoOperation && (target != "" || group != "") {
+		if !b.channelEnabled(b.channelSettings(ev.Channel)) {
+			b.logger.Debug("Ignoring point operation in disabled channel", "channel", ev.Channel)
+			return
+		}
+
+		b.logger.Info("Point operation detected", "text", ev.Text, "target", target, "isUser", isUser, "group", group)
+		switch {
+		case group != "":
+			b.handleUserGroupPointChange(ev, operation, group)
+		case operation == PointCheck:
 			b.handlePointCheckMessage(ev, target, isUser)
-		} else {
+		default:
 			b.handlePointChangeMessage(ev, operation, target, isUser)
 		}
 	}
@@ -253,8 +669,46 @@ func (b *Bot) handleEvents() {
 				switch ev := innerEvent.Data.(type) {
 				case *slackevents.MessageEvent:
 					b.handleMessageEvent(ev)
+				case *slackevents.AppMentionEvent:
+					b.handleAppMention(ev)
+				case *slackevents.AppHomeOpenedEvent:
+					b.handleAppHomeOpened(ev)
 				}
 			}
+		case socketmode.EventTypeInteractive:
+			callback, ok := evt.Data.(slack.InteractionCallback)
+			if !ok {
+				b.logger.Error("Unexpected interaction type", "data", evt.Data)
+				continue
+			}
+			b.handleInteraction(evt.Request, callback)
 		}
 	}
 }
+
+// handleInteraction acknowledges and processes shortcuts and modal submissions. Submissions are checked
+// before they are acknowledged, so that their errors are shown in the modal.
+func (b *Bot) handleInteraction(req *socketmode.Request, callback slack.InteractionCallback) {
+	b.logger.Debug("Received interaction", "type", callback.Type, "callback_id", callback.CallbackID)
+	switch {
+	case callback.Type == slack.InteractionTypeMessageAction && callback.CallbackID == kudosCallbackID:
+		b.ack(req)
+		b.handleKudosShortcut(callback)
+	case callback.Type == slack.InteractionTypeViewSubmission && callback.View.CallbackID == kudosCallbackID:
+		if errs := b.kudosErrors(callback); len(errs) > 0 {
+			b.ack(req, slack.NewErrorsViewSubmissionResponse(errs))
+			return
+		}
+		b.ack(req)
+		b.giveKudos(callback)
+	default:
+		b.ack(req)
+	}
+}
+
+// ack acknowledges a request from Slack, with an optional response payload
+func (b *Bot) ack(req *socketmode.Request, payload ...any) {
+	if err := b.socketClient.Ack(*req, payload...); err != nil {
+		b.logger.Error("Failed to acknowledge request", "error", err)
+	}
+}
@@ -0,0 +1,167 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"plusplusbot/infra/repository"
+)
+
+// errChannelSettingsUsage is returned for channel commands that cannot be parsed
+var errChannelSettingsUsage = errors.New("invalid channel settings command")
+
+// channelSettings returns the stored settings of a channel, or no settings if they cannot be loaded
+func (b *Bot) channelSettings(channel string) *repository.ChannelSettings {
+	settings, err := b.repo.GetChannelSettings(context.Background(), channel)
+	if err != nil {
+		b.logger.Error("Error getting channel settings", "channel", channel, "error", err)
+		return &repository.ChannelSettings{ChannelID: channel}
+	}
+	return settings
+}
+
+// channelEnabled reports whether the bot responds to point operations in a channel.
+// Channels without a setting are enabled unless the allowlist is used.
+func (b *Bot) channelEnabled(settings *repository.ChannelSettings) bool {
+	if settings.Enabled != nil {
+		return *settings.Enabled
+	}
+	return !b.channelAllowlist
+}
+
+// minusAllowed reports whether points can be taken away with -- in a channel
+func (b *Bot) minusAllowed(settings *repository.ChannelSettings) bool {
+	if settings.AllowMinus != nil {
+		return *settings.AllowMinus
+	}
+	return b.allowMinus
+}
+
+// isBotManager reports whether a user can change channel settings:
+// workspace admins and owners, and the configured bot managers
+func (b *Bot) isBotManager(user string) bool {
+	return slices.Contains(b.botManagers, user) || b.isWorkspaceAdmin(user)
+}
+
+// parseSwitch parses "on", "off" or "default" into a setting, where nil means the default
+func parseSwitch(value string) (*bool, error) {
+	switch strings.ToLower(value) {
+	case "on":
+		enabled := true
+		return &enabled, nil
+	case "off":
+		enabled := false
+		return &enabled, nil
+	case "default":
+		return nil, nil
+	}
+	return nil, errChannelSettingsUsage
+}
+
+// applyChannelSetting changes the settings of a channel according to the arguments of a channel command
+func applyChannelSetting(settings *repository.ChannelSettings, args []string) error {
+	if len(args) == 1 && strings.EqualFold(args[0], "reset") {
+		*settings = repository.ChannelSettings{ChannelID: settings.ChannelID}
+		return nil
+	}
+	if len(args) != 2 {
+		return errChannelSettingsUsage
+	}
+
+	key, value := strings.ToLower(args[0]), args[1]
+	switch key {
+	case "enabled", "minus":
+		setting, err := parseSwitch(value)
+		if err != nil {
+			return err
+		}
+		if key == "enabled" {
+			settings.Enabled = setting
+		} else {
+			settings.AllowMinus = setting
+		}
+	case "reply_mode":
+		if strings.EqualFold(value, "default") {
+			settings.ReplyMode = ""
+			return nil
+		}
+		mode, err := ParseReplyMode(strings.ToLower(value))
+		if err != nil {
+			return errChannelSettingsUsage
+		}
+		settings.ReplyMode = string(mode)
+	case "locale":
+		if strings.EqualFold(value, "default") {
+			settings.Locale = ""
+			return nil
+		}
+		if !localePattern.MatchString(value) {
+			return errChannelSettingsUsage
+		}
+		settings.Locale = value
+	default:
+		return errChannelSettingsUsage
+	}
+	return nil
+}
+
+// handleChannelCommand shows or changes the settings of the channel it is sent in:
+// "channel [enabled|minus on|off|default]", "channel reply_mode <mode>|default",
+// "channel locale <locale>|default" or "channel reset"
+func (b *Bot) handleChannelCommand(cmd command) {
+	ctx := context.Background()
+	settings, err := b.repo.GetChannelSettings(ctx, cmd.channel)
+	if err != nil {
+		b.logger.Error("Error getting channel settings", "error", err)
+		return
+	}
+
+	if len(cmd.args) > 0 {
+		messages := (*catalog.Load()).Messages(b.localeFor(cmd.channel, cmd.user))
+		if !b.isBotManager(cmd.user) {
+			b.postReply(cmd.channel, cmd.threadTS, reply{text: messages.NotBotManager})
+			return
+		}
+		if err := applyChannelSetting(settings, cmd.args); err != nil {
+			b.postReply(cmd.channel, cmd.threadTS, reply{text: messages.ChannelSettingsUsage})
+			return
+		}
+		if err := b.repo.PutChannelSettings(ctx, *settings); err != nil {
+			b.logger.Error("Error saving channel settings", "error", err)
+			return
+		}
+		b.logger.Info("Channel settings changed", "channel", cmd.channel, "user", cmd.user, "args", cmd.args)
+	}
+
+	b.postReply(cmd.channel, cmd.threadTS, reply{text: b.describeChannelSettings(cmd.channel, settings, cmd.user)})
+}
+
+// describeChannelSettings lists the effective settings of a channel, marking the ones not set for the channel
+func (b *Bot) describeChannelSettings(channel string, settings *repository.ChannelSettings, user string) string {
+	messages := (*catalog.Load()).Messages(b.localeFor(channel, user))
+	data := MessageData{Channel: "<#" + channel + ">"}
+
+	onOff := func(enabled bool) string {
+		if enabled {
+			return "on"
+		}
+		return "off"
+	}
+	line := func(key, value string, set bool) string {
+		if !set {
+			value += " (default)"
+		}
+		return fmt.Sprintf("`%s`: %s", key, value)
+	}
+
+	return strings.Join([]string{
+		renderText(messages.ChannelSettingsTitle, data),
+		line("enabled", onOff(b.channelEnabled(settings)), settings.Enabled != nil),
+		line("minus", onOff(b.minusAllowed(settings)), settings.AllowMinus != nil),
+		line("reply_mode", string(b.replyModeFor(channel)), settings.ReplyMode != ""),
+		line("locale", b.localeFor(channel, ""), settings.Locale != ""),
+	}, "\n")
+}
@@ -0,0 +1,159 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"plusplusbot/infra/repository"
+)
+
+func TestApplyChannelSetting(t *testing.T) {
+	on, off := true, false
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    repository.ChannelSettings
+		wantErr bool
+	}{
+		{"disable", []string{"enabled", "off"}, repository.ChannelSettings{ChannelID: "C1", Enabled: &off}, false},
+		{"allow minus", []string{"minus", "ON"}, repository.ChannelSettings{ChannelID: "C1", AllowMinus: &on}, false},
+		{"reply mode", []string{"reply_mode", "thread"}, repository.ChannelSettings{ChannelID: "C1", ReplyMode: "thread"}, false},
+		{"locale", []string{"locale", "ja"}, repository.ChannelSettings{ChannelID: "C1", Locale: "ja"}, false},
+		{"unknown reply mode", []string{"reply_mode", "loud"}, repository.ChannelSettings{}, true},
+		{"invalid switch", []string{"minus", "maybe"}, repository.ChannelSettings{}, true},
+		{"unknown key", []string{"colour", "blue"}, repository.ChannelSettings{}, true},
+		{"missing value", []string{"enabled"}, repository.ChannelSettings{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := repository.ChannelSettings{ChannelID: "C1"}
+			err := applyChannelSetting(&settings, tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("applyChannelSetting() error = nil, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyChannelSetting() error = %v", err)
+			}
+			if describe(settings) != describe(tt.want) {
+				t.Errorf("applyChannelSetting() = %s, want %s", describe(settings), describe(tt.want))
+			}
+		})
+	}
+}
+
+// describe formats channel settings for comparison
+func describe(s repository.ChannelSettings) string {
+	format := func(b *bool) string {
+		if b == nil {
+			return "nil"
+		}
+		if *b {
+			return "on"
+		}
+		return "off"
+	}
+	return strings.Join([]string{s.ChannelID, format(s.Enabled), format(s.AllowMinus), s.ReplyMode, s.Locale}, ",")
+}
+
+func TestSimulatorChannelSettings(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	sim.SetAdminUsers("UADMIN")
+
+	run := func(line string) string {
+		t.Helper()
+		out.Reset()
+		if err := sim.HandleLine(line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+		return out.String()
+	}
+
+	if got := run("U1 #announcements: <@UBOT> channel minus off"); got != "#announcements: Only bot managers can change the settings of this channel.\n" {
+		t.Errorf("channel command by a non-manager = %q", got)
+	}
+
+	got := run("UADMIN #announcements: <@UBOT> channel minus off")
+	want := "#announcements: *Settings for <#announcements>*\n" +
+		"`enabled`: on (default)\n" +
+		"`minus`: off\n" +
+		"`reply_mode`: channel (default)\n" +
+		"`locale`: en (default)\n"
+	if got != want {
+		t.Errorf("channel command = %q, want %q", got, want)
+	}
+
+	if got := run("U1 #announcements: <@U2>--"); !strings.Contains(got, "here") {
+		t.Errorf("-- in a channel without minus = %q, want the gentle message", got)
+	}
+	if got := run("U1 #announcements: <@U2>++"); !strings.Contains(got, "1 point") {
+		t.Errorf("++ in a channel without minus = %q, want a reply", got)
+	}
+
+	run("UADMIN #announcements: <@UBOT> channel locale ja")
+	if got := run("U1 #announcements: <@U2>=="); got != "#announcements: <@U2> は現在 1ポイント です。\n" {
+		t.Errorf("reply in a Japanese channel = %q", got)
+	}
+
+	run("UADMIN #announcements: <@UBOT> channel enabled off")
+	if got := run("U1 #announcements: <@U2>++"); got != "" {
+		t.Errorf("++ in a disabled channel = %q, want no reply", got)
+	}
+
+	if got := run("UADMIN #announcements: <@UBOT> channel reply_mode loud"); !strings.Contains(got, "使い方") {
+		t.Errorf("invalid channel command = %q, want the usage", got)
+	}
+}
+
+func TestSimulatorChannelAllowlist(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithChannelAllowlist(true)(sim.bot)
+	WithBotManagers([]string{"U9"})(sim.bot)
+
+	script := "U1 #general: <@U2>++\nU9 #kudos: <@UBOT> channel enabled on\nU1 #kudos: <@U2>++\n"
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if strings.Contains(out.String(), "#general") {
+		t.Errorf("the bot replied in a channel not on the allowlist: %q", out.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "#kudos: ") || !strings.Contains(last, "<@U2>") {
+		t.Errorf("the bot did not reply in an enabled channel: %q", out.String())
+	}
+}
+
+func TestSimulatorMinusDisabled(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	inner := sim.bot.repo.(*repository.PolicyRepository).UserPointsRepository
+	sim.bot.repo = repository.NewPolicyRepository(inner, repository.Policy{AllowMinus: false, FloorAtZero: true})
+	WithAllowMinus(false)(sim.bot)
+	sim.SetAdminUsers("UADMIN")
+
+	run := func(line string) string {
+		t.Helper()
+		out.Reset()
+		if err := sim.HandleLine(line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+		return out.String()
+	}
+
+	if got := run("U1 #general: <@U2>--"); !strings.Contains(got, "here") {
+		t.Errorf("-- with minus disabled = %q, want the gentle message", got)
+	}
+	if got := run("UADMIN #general: <@UBOT> channel"); !strings.Contains(got, "`minus`: off (default)") {
+		t.Errorf("channel settings = %q, want minus off by default", got)
+	}
+
+	run("UADMIN #general: <@UBOT> channel minus on")
+	if got := run("U1 #general: <@U2>--"); !strings.Contains(got, "0 points") {
+		t.Errorf("-- in a channel allowing minus = %q, want the total floored at zero", got)
+	}
+}
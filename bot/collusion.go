@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"plusplusbot/infra/repository"
+)
+
+// CollusionDetection configures the detection of users trading points back and forth
+type CollusionDetection struct {
+	// Threshold is how many times two users must give each other points within Window to be flagged;
+	// zero disables the detection
+	Threshold int
+	// Window is the time window reciprocal giving is counted in
+	Window time.Duration
+	// Dampen stops adding the points two flagged users give each other
+	Dampen bool
+	// ReportUsers are notified by direct message when a pair of users is flagged
+	ReportUsers []string
+}
+
+// reciprocalPair is a pair of users who gave each other points
+type reciprocalPair struct {
+	giver    string
+	target   string
+	given    int
+	returned int
+}
+
+// flagged reports whether both users gave each other points at least threshold times
+func (p reciprocalPair) flagged(threshold int) bool {
+	return threshold > 0 && min(p.given, p.returned) >= threshold
+}
+
+// countsAsGiving reports whether an event was a ++, including the ones dampened as reciprocal giving
+func countsAsGiving(event repository.PointEvent) bool {
+	return event.Delta > 0 || event.Dampened
+}
+
+// checkReciprocal counts how many times giver gave target points within the window, including this time,
+// and how many times target gave points back
+func (b *Bot) checkReciprocal(ctx context.Context, giver, target string, now time.Time) (reciprocalPair, error) {
+	pair := reciprocalPair{giver: giver, target: target, given: 1}
+	since := now.Add(-b.collusion.Window)
+
+	received, err := b.repo.ListPointEvents(ctx, target, since)
+	if err != nil {
+		return pair, err
+	}
+	for _, event := range received {
+		if event.Giver == giver && countsAsGiving(event) {
+			pair.given++
+		}
+	}
+
+	returned, err := b.repo.ListPointEvents(ctx, giver, since)
+	if err != nil {
+		return pair, err
+	}
+	for _, event := range returned {
+		if event.Giver == target && countsAsGiving(event) {
+			pair.returned++
+		}
+	}
+	return pair, nil
+}
+
+// reciprocalPairs returns the pairs of users flagged for reciprocal giving since the given time,
+// the most active first
+func (b *Bot) reciprocalPairs(ctx context.Context, since time.Time) ([]reciprocalPair, error) {
+	events, err := b.repo.ListPointEvents(ctx, "", since)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[[2]string]int{}
+	for _, event := range events {
+		if event.Giver != event.Target && countsAsGiving(event) {
+			counts[[2]string{event.Giver, event.Target}]++
+		}
+	}
+
+	var pairs []reciprocalPair
+	for users, given := range counts {
+		pair := reciprocalPair{giver: users[0], target: users[1], given: given, returned: counts[[2]string{users[1], users[0]}]}
+		// Each pair is counted in both directions, so keep one of them
+		if users[0] < users[1] && pair.flagged(b.collusion.Threshold) {
+			pairs = append(pairs, pair)
+		}
+	}
+	slices.SortFunc(pairs, func(a, b reciprocalPair) int {
+		if c := cmp.Compare(b.given+b.returned, a.given+a.returned); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.giver, b.giver)
+	})
+	return pairs, nil
+}
+
+// collusionReply creates the report of the pairs of users flagged for reciprocal giving
+func collusionReply(locale string, pairs []reciprocalPair) reply {
+	messages := (*catalog.Load()).Messages(locale)
+	if len(pairs) == 0 {
+		return reply{text: messages.CollusionNone}
+	}
+
+	lines := []string{messages.CollusionTitle}
+	for _, pair := range pairs {
+		data := newMessageData(pair.target, true, 0).withGiver(pair.giver)
+		data.pointsString = messages.formatPoints
+		data.Given = pair.given
+		data.Returned = pair.returned
+		lines = append(lines, "• "+renderText(messages.CollusionLine, data))
+	}
+	return reply{text: strings.Join(lines, "\n")}
+}
+
+// reportReciprocalPair notifies the report users of a newly flagged pair by direct message
+func (b *Bot) reportReciprocalPair(pair reciprocalPair) {
+	for _, user := range b.collusion.ReportUsers {
+		b.postReply(user, "", collusionReply(b.localeFor("", user), []reciprocalPair{pair}))
+	}
+}
+
+// handleCollusionCommand replies with the pairs of users flagged for reciprocal giving within the window:
+// "collusion". Only workspace admins and owners, and the report users, can see it.
+func (b *Bot) handleCollusionCommand(cmd command) {
+	locale := b.localeFor(cmd.channel, cmd.user)
+	if !slices.Contains(b.collusion.ReportUsers, cmd.user) && !b.isWorkspaceAdmin(cmd.user) {
+		b.postReply(cmd.channel, cmd.threadTS, reply{text: (*catalog.Load()).Messages(locale).NotAdmin})
+		return
+	}
+
+	pairs, err := b.reciprocalPairs(context.Background(), time.Now().Add(-b.collusion.Window))
+	if err != nil {
+		b.logger.Error("Error listing point events", "error", err)
+		return
+	}
+	b.postReply(cmd.channel, cmd.threadTS, collusionReply(locale, pairs))
+}
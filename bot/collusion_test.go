@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReciprocalPairFlagged(t *testing.T) {
+	tests := []struct {
+		pair      reciprocalPair
+		threshold int
+		want      bool
+	}{
+		{reciprocalPair{given: 3, returned: 3}, 3, true},
+		{reciprocalPair{given: 5, returned: 2}, 3, false},
+		{reciprocalPair{given: 3, returned: 4}, 3, true},
+		{reciprocalPair{given: 3, returned: 3}, 0, false},
+	}
+	for _, tt := range tests {
+		if got := tt.pair.flagged(tt.threshold); got != tt.want {
+			t.Errorf("%+v.flagged(%d) = %v, want %v", tt.pair, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestSimulatorCollusion(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithCollusionDetection(CollusionDetection{Threshold: 2, Window: time.Hour, Dampen: true, ReportUsers: []string{"UMOD"}})(sim.bot)
+
+	script := "U1 #general: <@U2>++\nU2 #general: <@U1>++\nU1 #general: <@U2>++\nU2 #general: <@U1>++\n"
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for target, want := range map[string]int{"U1": 1, "U2": 2} {
+		points, err := sim.bot.repo.GetPoints(ctx, target)
+		if err != nil {
+			t.Fatalf("GetPoints() error = %v", err)
+		}
+		if points != want {
+			t.Errorf("points of %s = %d, want %d", target, points, want)
+		}
+	}
+
+	report := "*Reciprocal giving*\n• <@U2> and <@U1> gave each other points 2 and 2 times\n"
+	if !strings.Contains(out.String(), "@UMOD: "+report) {
+		t.Errorf("output = %q, want the report sent to UMOD once", out.String())
+	}
+	if strings.Count(out.String(), "@UMOD") != 1 {
+		t.Errorf("output = %q, want one report", out.String())
+	}
+
+	events, err := sim.bot.repo.ListPointEvents(ctx, "U1", time.Time{})
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(events) != 2 || events[0].Dampened || !events[1].Dampened || events[1].Delta != 0 {
+		t.Errorf("ListPointEvents(U1) = %+v, want the second event dampened", events)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U3 #general: <@UBOT> collusion"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if got := out.String(); got != "#general: Only workspace admins can do that.\n" {
+		t.Errorf("collusion command by a user = %q", got)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("UMOD #general: <@UBOT> collusion"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if got := out.String(); got != "#general: *Reciprocal giving*\n• <@U1> and <@U2> gave each other points 2 and 2 times\n" {
+		t.Errorf("collusion command = %q", got)
+	}
+}
@@ -0,0 +1,277 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// defaultLeaderboardSize is the number of targets shown by the leaderboard command
+const defaultLeaderboardSize = 10
+
+// maxLeaderboardSize is the largest number of targets the leaderboard command shows
+const maxLeaderboardSize = 50
+
+// mentionPrefixPattern matches the mention of the bot at the start of a command, like "<@U123> "
+var mentionPrefixPattern = regexp.MustCompile(`^\s*<@[A-Z0-9]+>[\s:]*`)
+
+// mentionPattern matches a user mention given as a command argument, like "<@U123>" or "<@U123|name>"
+var mentionPattern = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|[^>]*)?>$`)
+
+// emojiArgPattern matches an emoji given as a command argument, like ":sake:"
+var emojiArgPattern = regexp.MustCompile(`^:([a-zA-Z0-9_+-]+):$`)
+
+// command is a command sent to the bot by mentioning it, like "@plusplusbot leaderboard 5"
+type command struct {
+	name     string
+	args     []string
+	channel  string
+	user     string
+	threadTS string
+}
+
+// commandHandler handles a command
+type commandHandler func(b *Bot, cmd command)
+
+// commandHandlers are the commands the bot responds to, keyed by name
+var commandHandlers = map[string]commandHandler{
+	"leaderboard": (*Bot).handleLeaderboardCommand,
+	"top":         (*Bot).handleLeaderboardCommand,
+	"notify":      (*Bot).handleNotifyCommand,
+	"channel":     (*Bot).handleChannelCommand,
+	"collusion":   (*Bot).handleCollusionCommand,
+	"givers":      (*Bot).handleGiversCommand,
+	"stats":       (*Bot).handleStatsCommand,
+	"digest":      (*Bot).handleDigestCommand,
+	"score":       (*Bot).handleScoreCommand,
+	"season":      (*Bot).handleSeasonCommand,
+	"badges":      (*Bot).handleBadgesCommand,
+}
+
+// parseCommand parses the text of a mention of the bot into a command name and its arguments
+func parseCommand(text string) (string, []string) {
+	fields := strings.Fields(mentionPrefixPattern.ReplaceAllString(text, ""))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), fields[1:]
+}
+
+// handleAppMention processes a mention of the bot as a command
+func (b *Bot) handleAppMention(ev *slackevents.AppMentionEvent) {
+	b.logger.Debug("Received app mention event", "event", ev)
+	name, args := parseCommand(ev.Text)
+	handler, ok := commandHandlers[name]
+	if !ok {
+		b.logger.Debug("Unknown command", "text", ev.Text)
+		return
+	}
+
+	b.logger.Info("Command received", "command", name, "args", args, "user", ev.User)
+	handler(b, command{
+		name:     name,
+		args:     args,
+		channel:  ev.Channel,
+		user:     ev.User,
+		threadTS: ev.ThreadTimeStamp,
+	})
+}
+
+// leaderboardSize returns the number of entries requested by the arguments of a leaderboard command
+func leaderboardSize(args []string) int {
+	if len(args) > 0 {
+		if n, err := strconv.Atoi(args[0]); err == nil && n > 0 {
+			return min(n, maxLeaderboardSize)
+		}
+	}
+	return defaultLeaderboardSize
+}
+
+// handleLeaderboardCommand replies with the targets with the most points: "leaderboard [size]"
+func (b *Bot) handleLeaderboardCommand(cmd command) {
+	records, err := b.rankedPoints(context.Background(), leaderboardSize(cmd.args))
+	if err != nil {
+		b.logger.Error("Error listing points", "error", err)
+		return
+	}
+
+	locale := b.localeFor(cmd.channel, cmd.user)
+	if b.decay.enabled() {
+		b.postReply(cmd.channel, cmd.threadTS, decayedLeaderboardReply(locale, records))
+		return
+	}
+	b.postReply(cmd.channel, cmd.threadTS, leaderboardReply(locale, records))
+}
+
+// handleGiversCommand replies with the users who gave the most points: "givers [size]"
+func (b *Bot) handleGiversCommand(cmd command) {
+	givers, err := b.repo.ListGivers(context.Background(), leaderboardSize(cmd.args))
+	if err != nil {
+		b.logger.Error("Error listing givers", "error", err)
+		return
+	}
+
+	b.postReply(cmd.channel, cmd.threadTS, giversReply(b.localeFor(cmd.channel, cmd.user), givers))
+}
+
+// handleStatsCommand replies with the points a user has received and given: "stats [@user]".
+// Without an argument, it replies with the stats of the sender.
+func (b *Bot) handleStatsCommand(cmd command) {
+	messages := (*catalog.Load()).Messages(b.localeFor(cmd.channel, cmd.user))
+	user := cmd.user
+	if len(cmd.args) > 0 {
+		matches := mentionPattern.FindStringSubmatch(cmd.args[0])
+		if matches == nil {
+			b.postReply(cmd.channel, cmd.threadTS, reply{text: messages.StatsUsage})
+			return
+		}
+		user = matches[1]
+	}
+
+	data, err := b.statsData(context.Background(), messages, user)
+	if err != nil {
+		b.logger.Error("Error getting stats", "error", err)
+		return
+	}
+	b.postReply(cmd.channel, cmd.threadTS, reply{text: renderText(messages.Stats, data)})
+}
+
+// statsData loads the points a user has received and given, and their giving streak, for the stats message
+func (b *Bot) statsData(ctx context.Context, messages *Messages, user string) (MessageData, error) {
+	points, err := b.repo.GetPoints(ctx, user)
+	if err != nil {
+		return MessageData{}, fmt.Errorf("failed to get points: %w", err)
+	}
+	stats, err := b.repo.GetGiverStats(ctx, user)
+	if err != nil {
+		return MessageData{}, fmt.Errorf("failed to get giver stats: %w", err)
+	}
+	streak, err := b.repo.GetGivingStreak(ctx, user)
+	if err != nil {
+		return MessageData{}, fmt.Errorf("failed to get giving streak: %w", err)
+	}
+
+	data := newMessageData(user, true, points)
+	data.pointsString = messages.formatPoints
+	data.Given = stats.Given
+	data.Streak, data.LongestStreak = b.currentStreak(*streak, time.Now()), streak.Longest
+	data.Recipients = stats.Recipients
+	if !stats.LastGiven.IsZero() {
+		data.LastGiven = slackDate(stats.LastGiven)
+	}
+	data.loadRank = func() int {
+		rank, err := b.rank(ctx, user)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			b.logger.Error("Error getting rank", "error", err)
+		}
+		return rank
+	}
+	return data, nil
+}
+
+// handleScoreCommand replies with the points a target received in a period, and its rank among the targets
+// who received points then: "score [@user|:emoji:] [this|last] [day|week|month|quarter]".
+// The target defaults to the sender, and the period to this week.
+func (b *Bot) handleScoreCommand(cmd command) {
+	messages := (*catalog.Load()).Messages(b.localeFor(cmd.channel, cmd.user))
+	target, isUser := cmd.user, true
+	args := cmd.args
+	if len(args) > 0 {
+		if matches := mentionPattern.FindStringSubmatch(args[0]); matches != nil {
+			target, args = matches[1], args[1:]
+		} else if matches := emojiArgPattern.FindStringSubmatch(args[0]); matches != nil {
+			target, isUser, args = matches[1], false, args[1:]
+		}
+	}
+	last := false
+	if len(args) > 0 && (strings.EqualFold(args[0], "this") || strings.EqualFold(args[0], "last")) {
+		last, args = strings.EqualFold(args[0], "last"), args[1:]
+	}
+	period := PeriodWeek
+	if len(args) > 0 {
+		p, err := ParsePeriod(args[0])
+		if err != nil {
+			b.postReply(cmd.channel, cmd.threadTS, reply{text: messages.ScoreUsage})
+			return
+		}
+		period, args = p, args[1:]
+	}
+	if len(args) > 0 {
+		b.postReply(cmd.channel, cmd.threadTS, reply{text: messages.ScoreUsage})
+		return
+	}
+
+	start := period.start(time.Now().In(b.timezone()))
+	if last {
+		start = period.previous(start)
+	}
+	end := period.end(start)
+
+	ctx := context.Background()
+	points, err := b.repo.GetPointsBetween(ctx, target, start, end)
+	if err != nil {
+		b.logger.Error("Error getting points", "error", err)
+		return
+	}
+
+	data := newMessageData(target, isUser, points)
+	data.pointsString = messages.formatPoints
+	data.Period = formatPeriod(start, end)
+	data.loadRank = func() int {
+		records, err := b.repo.ListPointsBetween(ctx, start, end, 0)
+		if err != nil {
+			b.logger.Error("Error listing points", "error", err)
+			return 0
+		}
+		rank := 0
+		for i, record := range records {
+			if i == 0 || record.Points != records[i-1].Points {
+				rank = i + 1
+			}
+			if record.UserID == target {
+				return rank
+			}
+		}
+		return 0
+	}
+	b.postReply(cmd.channel, cmd.threadTS, reply{text: renderText(messages.Score, data)})
+}
+
+// slackDate formats a time as a Slack date shown in the reader's time zone, falling back to the UTC date
+func slackDate(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{date_short_pretty}|%s>", t.Unix(), t.UTC().Format("2006-01-02"))
+}
+
+// handleNotifyCommand turns direct message notifications on or off for the user: "notify [on|off]".
+// Without an argument, it replies with the current setting.
+func (b *Bot) handleNotifyCommand(cmd command) {
+	ctx := context.Background()
+	settings, err := b.repo.GetUserSettings(ctx, cmd.user)
+	if err != nil {
+		b.logger.Error("Error getting user settings", "error", err)
+		return
+	}
+
+	if len(cmd.args) > 0 && (strings.EqualFold(cmd.args[0], "on") || strings.EqualFold(cmd.args[0], "off")) {
+		settings.Notify = strings.EqualFold(cmd.args[0], "on")
+		if err := b.repo.PutUserSettings(ctx, *settings); err != nil {
+			b.logger.Error("Error saving user settings", "error", err)
+			return
+		}
+	}
+
+	messages := (*catalog.Load()).Messages(b.localeFor(cmd.channel, cmd.user))
+	text := messages.NotificationsOff
+	if settings.Notify {
+		text = messages.NotificationsOn
+	}
+	b.postReply(cmd.channel, cmd.threadTS, reply{text: text})
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantName string
+		wantArgs []string
+	}{
+		{"<@UBOT> leaderboard", "leaderboard", []string{}},
+		{"<@UBOT>: Leaderboard 5", "leaderboard", []string{"5"}},
+		{"  <@UBOT>   top  3 ", "top", []string{"3"}},
+		{"<@UBOT>", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			name, args := parseCommand(tt.text)
+			if name != tt.wantName {
+				t.Errorf("parseCommand() name = %q, want %q", name, tt.wantName)
+			}
+			if len(args) != len(tt.wantArgs) || (len(args) > 0 && !reflect.DeepEqual(args, tt.wantArgs)) {
+				t.Errorf("parseCommand() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestLeaderboardCommand(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	if err := sim.HandleLine("U1 #general: <@UBOT> leaderboard"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if got := out.String(); got != "#general: Nobody has any points yet.\n" {
+		t.Errorf("empty leaderboard = %q", got)
+	}
+
+	script := strings.Join([]string{
+		"U1 #general: <@U2>++",
+		"U1 #general: <@U2>++",
+		"U1 #general: :sake:++",
+		"U1 #general: <@U3>++",
+		"U1 #general: :beer:--",
+	}, "\n")
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U1 #general: <@UBOT> leaderboard 3"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	want := "#general: *Leaderboard*\n" +
+		"1. <@U2>: 2 points\n" +
+		"2. <@U3>: 1 point\n" +
+		"2. :sake:: 1 point\n"
+	if got := out.String(); got != want {
+		t.Errorf("leaderboard = %q, want %q", got, want)
+	}
+}
+
+func TestUnknownCommandIsIgnored(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	if err := sim.HandleLine("U1 #general: <@UBOT> dance"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unknown command output = %q, want none", out.String())
+	}
+}
+
+func TestGiversAndStatsCommands(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	run := func(line string) string {
+		t.Helper()
+		out.Reset()
+		if err := sim.HandleLine(line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+		return out.String()
+	}
+
+	if got := run("U1 #general: <@UBOT> givers"); got != "#general: Nobody has given any points yet.\n" {
+		t.Errorf("empty givers = %q", got)
+	}
+
+	for _, line := range []string{
+		"U1 #general: <@U2>++",
+		"U1 #general: <@U2>++",
+		"U1 #general: :sake:++",
+		"U1 #general: <@U3>--",
+		"U2 #general: <@U1>++",
+	} {
+		run(line)
+	}
+
+	want := "#general: *Top Givers*\n" +
+		"1. <@U1>: 3 points\n" +
+		"2. <@U2>: 1 point\n"
+	if got := run("U3 #general: <@UBOT> givers"); got != want {
+		t.Errorf("givers = %q, want %q", got, want)
+	}
+
+	got := run("U3 #general: <@UBOT> stats <@U1|alice>")
+	if !strings.HasPrefix(got, "#general: <@U1> has 1 point (#2) and has given 3 points to 2 targets, most recently <!date^") {
+		t.Errorf("stats = %q", got)
+	}
+	if !strings.HasSuffix(got, ". Giving streak: 1 day (longest 1).\n") {
+		t.Errorf("stats streak = %q", got)
+	}
+	if got := run("U3 #general: <@UBOT> stats"); got != "#general: <@U3> has -1 point (#4) and has given 0 points to 0 targets.\n" {
+		t.Errorf("stats of the sender = %q", got)
+	}
+	if got := run("U3 #general: <@UBOT> stats :sake:"); got != "#general: Usage: `stats [@user]`\n" {
+		t.Errorf("stats with an invalid argument = %q", got)
+	}
+}
+
+func TestScoreCommand(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	run := func(line string) string {
+		t.Helper()
+		out.Reset()
+		if err := sim.HandleLine(line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+		return out.String()
+	}
+
+	for _, line := range []string{
+		"U1 #general: <@U2>++",
+		"U3 #general: <@U2>++",
+		"U1 #general: :sake:++",
+		"U1 #general: :sake:++",
+		"U2 #general: :sake:++",
+	} {
+		run(line)
+	}
+
+	now := time.Now().UTC()
+	month := PeriodMonth.start(now)
+	if got, want := run("U1 #general: <@UBOT> score <@U2> this month"), "#general: <@U2> got 2 points in "+formatPeriod(month, PeriodMonth.end(month))+" (#2).\n"; got != want {
+		t.Errorf("score this month = %q, want %q", got, want)
+	}
+	week := PeriodWeek.start(now)
+	if got, want := run("U2 #general: <@UBOT> score :sake:"), "#general: :sake: got 3 points in "+formatPeriod(week, PeriodWeek.end(week))+" (#1).\n"; got != want {
+		t.Errorf("score this week = %q, want %q", got, want)
+	}
+	lastQuarter := PeriodQuarter.previous(PeriodQuarter.start(now))
+	if got, want := run("U2 #general: <@UBOT> score last quarter"), "#general: <@U2> got 0 points in "+formatPeriod(lastQuarter, PeriodQuarter.end(lastQuarter))+".\n"; got != want {
+		t.Errorf("score of the sender last quarter = %q, want %q", got, want)
+	}
+	if got := run("U1 #general: <@UBOT> score <@U2> this year"); got != "#general: Usage: `score [@user|:emoji:] [this|last] [day|week|month|quarter]`\n" {
+		t.Errorf("score with an unknown period = %q", got)
+	}
+}
@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"plusplusbot/infra/repository"
+)
+
+// Decay makes old points count less in rankings, while the totals themselves are kept as they are.
+// Scores are computed from the point history when rankings are read. The zero value disables decay.
+type Decay struct {
+	// HalfLife is how long it takes points to lose half of their weight, for exponential decay
+	HalfLife time.Duration
+	// Rate is the share of their weight, between 0 and 1, points lose at the start of every Period,
+	// for per-period decay
+	Rate   float64
+	Period Period
+}
+
+// enabled reports whether the decay changes any score
+func (d Decay) enabled() bool {
+	return d.HalfLife > 0 || (d.Rate > 0 && d.Period != "")
+}
+
+// weight returns how much a point given at t counts at now, from 1 down towards 0
+func (d Decay) weight(t, now time.Time) float64 {
+	if !now.After(t) {
+		return 1
+	}
+	if d.HalfLife > 0 {
+		return math.Exp2(-float64(now.Sub(t)) / float64(d.HalfLife))
+	}
+	return math.Pow(1-d.Rate, float64(d.Period.between(t, now)))
+}
+
+// negligibleWeight is the weight below which points are left out of the point history read for decayed scores
+const negligibleWeight = 0.001
+
+// maxDecayHorizon is how far back the point history is read for decayed scores at most; with slower decay,
+// the whole history is read
+const maxDecayHorizon = 10 * 365 * 24 * time.Hour
+
+// horizon returns the time points given before weigh less than negligibleWeight at now, or the zero time
+// if that is further back than maxDecayHorizon
+func (d Decay) horizon(now time.Time) time.Time {
+	var horizon time.Time
+	if d.HalfLife > 0 {
+		halfLives := math.Log2(1 / negligibleWeight)
+		if float64(d.HalfLife)*halfLives >= float64(maxDecayHorizon) {
+			return time.Time{}
+		}
+		horizon = now.Add(-time.Duration(float64(d.HalfLife) * halfLives))
+	} else {
+		// Points weigh less than negligibleWeight once n period boundaries have passed since they were given
+		n := math.Max(math.Ceil(math.Log(negligibleWeight)/math.Log(1-d.Rate)), 1)
+		if n*float64(d.Period.end(time.Time{}).Sub(time.Time{})) >= float64(maxDecayHorizon) {
+			return time.Time{}
+		}
+		back := int(n) - 1
+		start := d.Period.start(now)
+		switch d.Period {
+		case PeriodWeek:
+			horizon = start.AddDate(0, 0, -7*back)
+		case PeriodMonth:
+			horizon = start.AddDate(0, -back, 0)
+		case PeriodQuarter:
+			horizon = start.AddDate(0, -3*back, 0)
+		default:
+			horizon = start.AddDate(0, 0, -back)
+		}
+	}
+	return horizon
+}
+
+// between returns the number of period boundaries from t to now, in the location of now
+func (p Period) between(t, now time.Time) int {
+	from, to := p.start(t.In(now.Location())), p.start(now)
+	switch p {
+	case PeriodMonth, PeriodQuarter:
+		months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+		if p == PeriodQuarter {
+			return months / 3
+		}
+		return months
+	case PeriodWeek:
+		return int(math.Round(to.Sub(from).Hours() / 24 / 7))
+	default:
+		// Rounding absorbs the days shortened or lengthened by daylight saving time
+		return int(math.Round(to.Sub(from).Hours() / 24))
+	}
+}
+
+// decayedScoresTTL is how long decayed scores are reused before they are computed again from the point history
+const decayedScoresTTL = time.Minute
+
+// decayedScores keeps the decayed scores last computed, so that rankings read in a burst, like the ranks
+// in the replies to point changes, don't each go through the point history
+type decayedScores struct {
+	mu       sync.Mutex
+	records  []repository.UserPoints
+	computed time.Time
+}
+
+// decayedPoints returns every target with its decayed score in Points, highest first. The scores are
+// reused for decayedScoresTTL, so rankings can lag behind point changes for that long.
+func (b *Bot) decayedPoints(ctx context.Context) ([]repository.UserPoints, error) {
+	b.decayed.mu.Lock()
+	defer b.decayed.mu.Unlock()
+	if time.Since(b.decayed.computed) >= decayedScoresTTL {
+		records, err := b.computeDecayedPoints(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b.decayed.records, b.decayed.computed = records, time.Now()
+	}
+	return slices.Clone(b.decayed.records), nil
+}
+
+// computeDecayedPoints computes the decayed score of every target from the point history since the horizon
+// of the decay. Points missing from that history, given before the horizon or before the history was kept,
+// decay from the first change in the history of the target, or from its last change if it has none.
+func (b *Bot) computeDecayedPoints(ctx context.Context) ([]repository.UserPoints, error) {
+	records, err := b.repo.ListPoints(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(b.timezone())
+	events, err := b.repo.ListPointEvents(ctx, "", b.decay.horizon(now))
+	if err != nil {
+		return nil, err
+	}
+
+	scores := map[string]float64{}
+	history := map[string]int{}
+	first := map[string]time.Time{}
+	for _, event := range events {
+		scores[event.Target] += float64(event.Delta) * b.decay.weight(event.Timestamp, now)
+		history[event.Target] += event.Delta
+		if _, ok := first[event.Target]; !ok {
+			first[event.Target] = event.Timestamp
+		}
+	}
+
+	for i, record := range records {
+		since, ok := first[record.UserID]
+		if !ok {
+			since = record.LastModified
+		}
+		score := scores[record.UserID] + float64(record.Points-history[record.UserID])*b.decay.weight(since, now)
+		records[i].Points = int(math.Round(score))
+	}
+	// The records come ordered by their totals, which break ties between equal scores
+	slices.SortStableFunc(records, func(a, b repository.UserPoints) int {
+		return cmp.Compare(b.Points, a.Points)
+	})
+	return records, nil
+}
+
+// decayedLeaderboardReply creates the leaderboard of the decayed scores
+func decayedLeaderboardReply(locale string, records []repository.UserPoints) reply {
+	messages := (*catalog.Load()).Messages(locale)
+	return rankingReply(messages, messages.LeaderboardDecayedTitle, messages.LeaderboardEmpty, records)
+}
+
+// rankedPoints lists the targets ranked by their scores: their totals, or their decayed scores
+// when decay is enabled. A limit of zero or less lists all targets.
+func (b *Bot) rankedPoints(ctx context.Context, limit int) ([]repository.UserPoints, error) {
+	if !b.decay.enabled() {
+		return b.repo.ListPoints(ctx, limit)
+	}
+	records, err := b.decayedPoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// rank returns the 1-based position of a target in the rankings; targets with equal scores share a rank.
+// It returns repository.ErrNotFound if the target has no points record.
+func (b *Bot) rank(ctx context.Context, target string) (int, error) {
+	if !b.decay.enabled() {
+		return b.repo.GetRank(ctx, target)
+	}
+	records, err := b.decayedPoints(ctx)
+	if err != nil {
+		return 0, err
+	}
+	rank := 0
+	for i, record := range records {
+		if i == 0 || record.Points != records[i-1].Points {
+			rank = i + 1
+		}
+		if record.UserID == target {
+			return rank, nil
+		}
+	}
+	return 0, repository.ErrNotFound
+}
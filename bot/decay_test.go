@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"plusplusbot/infra/repository"
+)
+
+func TestDecayWeight(t *testing.T) {
+	now := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		decay Decay
+		t     time.Time
+		want  float64
+	}{
+		{"half-life", Decay{HalfLife: 30 * 24 * time.Hour}, now.AddDate(0, 0, -30), 0.5},
+		{"two half-lives", Decay{HalfLife: 30 * 24 * time.Hour}, now.AddDate(0, 0, -60), 0.25},
+		{"future", Decay{HalfLife: time.Hour}, now.Add(time.Hour), 1},
+		{"same month", Decay{Rate: 0.1, Period: PeriodMonth}, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), 1},
+		{"two months", Decay{Rate: 0.1, Period: PeriodMonth}, time.Date(2025, 3, 31, 23, 0, 0, 0, time.UTC), 0.81},
+		{"last quarter", Decay{Rate: 0.5, Period: PeriodQuarter}, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), 0.5},
+		{"a week ago", Decay{Rate: 0.5, Period: PeriodWeek}, now.AddDate(0, 0, -7), 0.5},
+		{"days", Decay{Rate: 0.5, Period: PeriodDay}, now.AddDate(0, 0, -3), 0.125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.decay.weight(tt.t, now); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("weight(%v, %v) = %v, want %v", tt.t, now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecayHorizon(t *testing.T) {
+	now := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		decay Decay
+		want  time.Time
+	}{
+		{"half-life", Decay{HalfLife: 24 * time.Hour}, now.Add(-time.Duration(math.Log2(1000) * float64(24*time.Hour)))},
+		{"slow half-life", Decay{HalfLife: 2 * 365 * 24 * time.Hour}, time.Time{}},
+		// 0.5^10 is the first power of 0.5 below 0.001
+		{"days", Decay{Rate: 0.5, Period: PeriodDay}, time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)},
+		{"months", Decay{Rate: 0.5, Period: PeriodMonth}, time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)},
+		{"everything", Decay{Rate: 1, Period: PeriodWeek}, time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{"slow rate", Decay{Rate: 0.01, Period: PeriodMonth}, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.decay.horizon(now)
+			if !got.Equal(tt.want) {
+				t.Errorf("horizon(%v) = %v, want %v", now, got, tt.want)
+			}
+			// Points given at the horizon weigh less than negligibleWeight, and points given after it more
+			if !got.IsZero() && (tt.decay.weight(got.Add(-time.Second), now) >= negligibleWeight || tt.decay.weight(got.Add(time.Second), now) < negligibleWeight) {
+				t.Errorf("weight around horizon %v = %v, %v", got, tt.decay.weight(got.Add(-time.Second), now), tt.decay.weight(got.Add(time.Second), now))
+			}
+		})
+	}
+}
+
+func TestDecayedLeaderboard(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithDecay(Decay{HalfLife: 30 * 24 * time.Hour})(sim.bot)
+
+	// U2 got 8 points two half-lives ago, U3 got 4 points one half-life ago and 1 point now
+	ctx := context.Background()
+	now := time.Now()
+	events := []repository.PointEvent{
+		{Target: "U2", Timestamp: now.AddDate(0, 0, -60), Giver: "U1", Delta: 8},
+		{Target: "U3", Timestamp: now.AddDate(0, 0, -30), Giver: "U1", Delta: 4},
+	}
+	for _, event := range events {
+		if err := sim.bot.repo.AddPoints(ctx, event.Target, event.Delta, true); err != nil {
+			t.Fatalf("AddPoints() error = %v", err)
+		}
+		if err := sim.bot.repo.AddPointEvent(ctx, event); err != nil {
+			t.Fatalf("AddPointEvent() error = %v", err)
+		}
+	}
+	// Points missing from the history decay from the last change of the target
+	if err := sim.bot.repo.AddPoints(ctx, "sake", 1, false); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if err := sim.HandleLine("U1 #general: <@U3>++"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U1 #general: <@UBOT> leaderboard"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	want := "#general: *Leaderboard (recent points count more)*\n" +
+		"1. <@U3>: 3 points\n" +
+		"2. <@U2>: 2 points\n" +
+		"3. :sake:: 1 point\n"
+	if got := out.String(); got != want {
+		t.Errorf("leaderboard = %q, want %q", got, want)
+	}
+
+	// The totals are kept as they are
+	if points, err := sim.bot.repo.GetPoints(ctx, "U2"); err != nil || points != 8 {
+		t.Errorf("GetPoints(U2) = %d, %v, want 8", points, err)
+	}
+	if rank, err := sim.bot.rank(ctx, "U2"); err != nil || rank != 2 {
+		t.Errorf("rank(U2) = %d, %v, want 2", rank, err)
+	}
+}
+
+func TestDecayedPointsCached(t *testing.T) {
+	sim, _, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithDecay(Decay{HalfLife: 30 * 24 * time.Hour})(sim.bot)
+
+	ctx := context.Background()
+	for _, line := range []string{"U1 #general: <@U2>++", "U1 #general: <@U2>++", "U1 #general: <@U3>++"} {
+		if err := sim.HandleLine(line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+	}
+	if rank, err := sim.bot.rank(ctx, "U3"); err != nil || rank != 2 {
+		t.Fatalf("rank(U3) = %d, %v, want 2", rank, err)
+	}
+
+	// The scores are reused until they expire
+	if err := sim.bot.repo.AddPoints(ctx, "U3", 5, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if rank, err := sim.bot.rank(ctx, "U3"); err != nil || rank != 2 {
+		t.Errorf("cached rank(U3) = %d, %v, want 2", rank, err)
+	}
+	sim.bot.decayed.computed = time.Now().Add(-decayedScoresTTL)
+	if rank, err := sim.bot.rank(ctx, "U3"); err != nil || rank != 1 {
+		t.Errorf("rank(U3) after expiry = %d, %v, want 1", rank, err)
+	}
+}
@@ -0,0 +1,280 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"plusplusbot/schedule"
+)
+
+// digestSize is the number of entries in each section of a digest
+const digestSize = 5
+
+// Digest is a summary of the points given in a period, posted to a channel on a schedule
+type Digest struct {
+	// Schedule is a cron-like schedule, like "0 9 * * 1" for every Monday at 9:00
+	Schedule string
+	// Period is the period summarized; each post covers the last complete one
+	Period Period
+	// Channel is the channel the digest is posted to
+	Channel string
+}
+
+// digestSummary is what happened in a period, computed from the point history
+type digestSummary struct {
+	// recipients and givers have the points received and given in Delta
+	recipients []MessageData
+	givers     []MessageData
+	// movers climbed the leaderboard, from PreviousRank to Rank
+	movers     []MessageData
+	milestones []MessageData
+	// reasons have the number of times they were given in Given
+	reasons []MessageData
+	// events is the number of point changes in the period
+	events int
+}
+
+// summarizePeriod computes the digest of the points given from start to end, excluding end
+func (b *Bot) summarizePeriod(ctx context.Context, start, end time.Time) (digestSummary, error) {
+	var summary digestSummary
+	events, err := b.repo.ListPointEvents(ctx, "", start)
+	if err != nil {
+		return summary, err
+	}
+	records, err := b.repo.ListPoints(ctx, 0)
+	if err != nil {
+		return summary, err
+	}
+
+	endTotals := map[string]int{}
+	users := map[string]bool{}
+	for _, record := range records {
+		endTotals[record.UserID] = record.Points
+		users[record.UserID] = isUserTarget(record)
+	}
+	isUser := func(target string) bool {
+		if user, ok := users[target]; ok {
+			return user
+		}
+		return userIDPattern.MatchString(target)
+	}
+
+	received := map[string]int{}
+	given := map[string]int{}
+	reasons := map[string]int{}
+	// reasonTexts keeps the first spelling of each reason, which are counted regardless of case
+	reasonTexts := map[string]string{}
+	for _, event := range events {
+		if !event.Timestamp.Before(end) {
+			// Roll the current totals back to the end of the period
+			endTotals[event.Target] -= event.Delta
+			continue
+		}
+		summary.events++
+		received[event.Target] += event.Delta
+		if event.Delta > 0 {
+			given[event.Giver] += event.Delta
+		}
+		if reason := strings.ToLower(event.Reason); reason != "" && countsAsGiving(event) {
+			if _, ok := reasonTexts[reason]; !ok {
+				reasonTexts[reason] = event.Reason
+			}
+			reasons[reason]++
+		}
+	}
+
+	startTotals := map[string]int{}
+	for target, points := range endTotals {
+		startTotals[target] = points - received[target]
+	}
+	startRanks, endRanks := rankTotals(startTotals), rankTotals(endTotals)
+
+	for _, entry := range topEntries(received) {
+		data := newMessageData(entry.key, isUser(entry.key), endTotals[entry.key])
+		data.Delta, data.Rank = entry.value, entry.rank
+		summary.recipients = append(summary.recipients, data)
+	}
+	for _, entry := range topEntries(given) {
+		data := newMessageData(entry.key, true, 0)
+		data.Delta, data.Rank = entry.value, entry.rank
+		summary.givers = append(summary.givers, data)
+	}
+
+	climbed := map[string]int{}
+	for target := range received {
+		climbed[target] = startRanks[target] - endRanks[target]
+	}
+	for _, entry := range topEntries(climbed) {
+		data := newMessageData(entry.key, isUser(entry.key), endTotals[entry.key])
+		data.Previous = startTotals[entry.key]
+		data.Delta = received[entry.key]
+		data.Rank, data.PreviousRank = endRanks[entry.key], startRanks[entry.key]
+		summary.movers = append(summary.movers, data)
+	}
+
+	for _, target := range slices.Sorted(maps.Keys(received)) {
+		milestone, messageType, ok := crossedMilestone(startTotals[target], endTotals[target], b.milestones, nil)
+		if ok && messageType == MilestoneMessage {
+			data := newMessageData(target, isUser(target), endTotals[target])
+			data.Milestone = milestone
+			summary.milestones = append(summary.milestones, data)
+		}
+	}
+	slices.SortStableFunc(summary.milestones, func(a, b MessageData) int {
+		return cmp.Compare(b.Milestone, a.Milestone)
+	})
+
+	for _, entry := range topEntries(reasons) {
+		data := MessageData{Reason: reasonTexts[entry.key], Given: entry.value, Rank: entry.rank}
+		summary.reasons = append(summary.reasons, data)
+	}
+	return summary, nil
+}
+
+// rankedEntry is a key with a positive value and its rank among the others
+type rankedEntry struct {
+	key   string
+	value int
+	rank  int
+}
+
+// topEntries returns the entries with the largest positive values, at most digestSize of them.
+// Entries with equal values share a rank.
+func topEntries(values map[string]int) []rankedEntry {
+	var entries []rankedEntry
+	for key, value := range values {
+		if value > 0 {
+			entries = append(entries, rankedEntry{key: key, value: value})
+		}
+	}
+	slices.SortFunc(entries, func(a, b rankedEntry) int {
+		if c := cmp.Compare(b.value, a.value); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+	for i := range entries {
+		entries[i].rank = i + 1
+		if i > 0 && entries[i].value == entries[i-1].value {
+			entries[i].rank = entries[i-1].rank
+		}
+	}
+	return entries[:min(len(entries), digestSize)]
+}
+
+// rankTotals returns the leaderboard rank of every target, sharing ranks between equal totals
+func rankTotals(totals map[string]int) map[string]int {
+	targets := slices.SortedFunc(maps.Keys(totals), func(a, b string) int {
+		return cmp.Compare(totals[b], totals[a])
+	})
+	ranks := make(map[string]int, len(targets))
+	for i, target := range targets {
+		ranks[target] = i + 1
+		if i > 0 && totals[target] == totals[targets[i-1]] {
+			ranks[target] = ranks[targets[i-1]]
+		}
+	}
+	return ranks
+}
+
+// digestReply creates the digest of a period: a section for each of the recipients, givers, movers,
+// milestones and reasons, skipping empty ones
+func digestReply(locale, period string, summary digestSummary) reply {
+	messages := (*catalog.Load()).Messages(locale)
+	data := MessageData{Period: period}
+	if summary.events == 0 {
+		return reply{text: renderText(messages.DigestEmpty, data)}
+	}
+
+	lines := []string{renderText(messages.DigestTitle, data)}
+	sections := []struct {
+		title   string
+		line    string
+		entries []MessageData
+	}{
+		{messages.DigestRecipients, messages.DigestRow, summary.recipients},
+		{messages.DigestGivers, messages.DigestRow, summary.givers},
+		{messages.DigestMovers, messages.DigestMover, summary.movers},
+		{messages.DigestMilestones, messages.DigestMilestone, summary.milestones},
+		{messages.DigestReasons, messages.DigestReason, summary.reasons},
+	}
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		lines = append(lines, "", section.title)
+		for _, entry := range section.entries {
+			entry.Period = period
+			entry.pointsString = messages.formatPoints
+			lines = append(lines, renderText(section.line, entry))
+		}
+	}
+	return reply{text: strings.Join(lines, "\n")}
+}
+
+// postDigest posts the digest of the period from start to end, excluding end, to a channel
+func (b *Bot) postDigest(channel, threadTS, user string, start, end time.Time) {
+	summary, err := b.summarizePeriod(context.Background(), start, end)
+	if err != nil {
+		b.logger.Error("Error summarizing points", "error", err)
+		return
+	}
+	b.postReply(channel, threadTS, digestReply(b.localeFor(channel, user), formatPeriod(start, end), summary))
+}
+
+// startDigests posts every digest on its schedule, in the background
+func (b *Bot) startDigests() {
+	for _, digest := range b.digests {
+		s, err := schedule.Parse(digest.Schedule)
+		if err != nil {
+			b.logger.Error("Invalid digest schedule", "channel", digest.Channel, "error", err)
+			continue
+		}
+		go b.runDigest(digest, s)
+	}
+}
+
+// runDigest posts a digest each time its schedule fires, covering the last complete period
+func (b *Bot) runDigest(digest Digest, s *schedule.Schedule) {
+	for {
+		next := s.Next(time.Now().In(b.timezone()))
+		if next.IsZero() {
+			b.logger.Warn("Digest schedule never fires", "schedule", digest.Schedule)
+			return
+		}
+		time.Sleep(time.Until(next))
+
+		end := digest.Period.start(next)
+		b.logger.Info("Posting digest", "channel", digest.Channel, "period", digest.Period)
+		b.postDigest(digest.Channel, "", "", digest.Period.previous(end), end)
+	}
+}
+
+// handleDigestCommand replies with the digest of the current period so far: "digest [day|week|month|quarter]".
+// The period defaults to the week.
+func (b *Bot) handleDigestCommand(cmd command) {
+	period := PeriodWeek
+	if len(cmd.args) > 0 {
+		p, err := ParsePeriod(cmd.args[0])
+		if err != nil {
+			b.postReply(cmd.channel, cmd.threadTS, reply{text: (*catalog.Load()).Messages(b.localeFor(cmd.channel, cmd.user)).DigestUsage})
+			return
+		}
+		period = p
+	}
+
+	start := period.start(time.Now().In(b.timezone()))
+	b.postDigest(cmd.channel, cmd.threadTS, cmd.user, start, period.end(start))
+}
+
+// timezone returns the location period boundaries are computed in
+func (b *Bot) timezone() *time.Location {
+	if b.location == nil {
+		return time.UTC
+	}
+	return b.location
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeriodStart(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// Sunday
+	sunday := time.Date(2025, 5, 18, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period Period
+		t      time.Time
+		start  time.Time
+		end    time.Time
+	}{
+		{PeriodDay, sunday, time.Date(2025, 5, 18, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{PeriodWeek, sunday, time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{PeriodMonth, sunday, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{PeriodQuarter, sunday, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		// It is already Monday in Tokyo
+		{PeriodWeek, sunday.In(tokyo), time.Date(2025, 5, 19, 0, 0, 0, 0, tokyo), time.Date(2025, 5, 26, 0, 0, 0, 0, tokyo)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			start := tt.period.start(tt.t)
+			if !start.Equal(tt.start) {
+				t.Errorf("start(%v) = %v, want %v", tt.t, start, tt.start)
+			}
+			if end := tt.period.end(start); !end.Equal(tt.end) {
+				t.Errorf("end(%v) = %v, want %v", start, end, tt.end)
+			}
+		})
+	}
+
+	if got, want := PeriodWeek.previous(time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)), time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("previous() = %v, want %v", got, want)
+	}
+	if got := formatPeriod(time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)); got != "2025-05-12 – 2025-05-18" {
+		t.Errorf("formatPeriod() = %q", got)
+	}
+}
+
+func TestSimulatorDigest(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithMilestones([]int{2}, nil)(sim.bot)
+
+	// Points given before the period
+	ctx := context.Background()
+	for _, target := range []string{"U3", "U4"} {
+		if err := sim.bot.repo.AddPoints(ctx, target, 1, true); err != nil {
+			t.Fatalf("AddPoints() error = %v", err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	script := "U1 #general: for the review <@U2>++\nU3 #general: For the review <@U2>++\nU2 #general: thanks <@U1>++\nU1 #general: :sake:++\n"
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	end := time.Now().Add(time.Hour)
+
+	out.Reset()
+	sim.bot.postDigest("CDIGEST", "", "", start, end)
+	want := "#CDIGEST: *Digest for " + formatPeriod(start, end) + "*\n\n" +
+		"*Top recipients*\n1. <@U2>: 2 points\n2. <@U1>: 1 point\n2. :sake:: 1 point\n\n" +
+		"*Top givers*\n1. <@U1>: 2 points\n2. <@U2>: 1 point\n2. <@U3>: 1 point\n\n" +
+		"*Biggest movers*\n• <@U2> climbed from #3 to #1\n• <@U1> climbed from #3 to #2\n• :sake: climbed from #3 to #2\n\n" +
+		"*New milestones*\n• <@U2> reached 2 points\n\n" +
+		"*Most common reasons*\n• for the review (2 times)\n• thanks (1 time)\n"
+	if got := out.String(); got != want {
+		t.Errorf("digest = %q, want %q", got, want)
+	}
+
+	// Changes after the period are not counted
+	out.Reset()
+	sim.bot.postDigest("CDIGEST", "", "", start.Add(-time.Hour), start)
+	if got := out.String(); !strings.HasPrefix(got, "#CDIGEST: No points were given in ") {
+		t.Errorf("empty digest = %q", got)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U1 #general: <@UBOT> digest day"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "*Top recipients*\n1. <@U2>: 2 points") {
+		t.Errorf("digest command = %q", got)
+	}
+
+	out.Reset()
+	if err := sim.HandleLine("U1 #general: <@UBOT> digest year"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if got := out.String(); got != "#general: Usage: `digest [day|week|month|quarter]`\n" {
+		t.Errorf("digest command with an unknown period = %q", got)
+	}
+}
@@ -0,0 +1,129 @@
+package bot
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// kudosCallbackID identifies the "Give kudos for this message" message shortcut and its modal
+const kudosCallbackID = "give_kudos"
+
+// maxKudosPoints is the most points the kudos modal lets a giver choose
+const maxKudosPoints = 5
+
+// Block IDs of the inputs of the kudos modal, and the action ID of their elements
+const (
+	kudosRecipientBlock = "recipient"
+	kudosPointsBlock    = "points"
+	kudosReasonBlock    = "reason"
+	kudosInputAction    = "input"
+)
+
+// kudosMessage is the message kudos are given for, kept in the private metadata of the kudos modal
+type kudosMessage struct {
+	Channel         string `json:"channel"`
+	TimeStamp       string `json:"ts"`
+	ThreadTimeStamp string `json:"thread_ts,omitempty"`
+}
+
+// handleKudosShortcut opens the kudos modal for a message, with its author as the recipient
+func (b *Bot) handleKudosShortcut(callback slack.InteractionCallback) {
+	author := callback.Message.User
+	if author == "" {
+		b.logger.Info("Ignoring kudos for a message without a user author", "channel", callback.Channel.ID)
+		return
+	}
+	metadata, err := json.Marshal(kudosMessage{
+		Channel:         callback.Channel.ID,
+		TimeStamp:       callback.Message.Timestamp,
+		ThreadTimeStamp: callback.Message.ThreadTimestamp,
+	})
+	if err != nil {
+		b.logger.Error("Error encoding kudos message", "error", err)
+		return
+	}
+
+	messages := (*catalog.Load()).Messages(b.localeFor(callback.Channel.ID, callback.User.ID))
+	if _, err := b.api.OpenView(callback.TriggerID, kudosModal(messages, author, string(metadata))); err != nil {
+		b.logger.Error("Error opening kudos modal", "error", err)
+	}
+}
+
+// kudosModal creates the modal giving points to recipient: who to give them to, how many, and why
+func kudosModal(messages *Messages, recipient, metadata string) slack.ModalViewRequest {
+	plainText := func(text string) *slack.TextBlockObject {
+		return slack.NewTextBlockObject(slack.PlainTextType, text, false, false)
+	}
+
+	options := make([]*slack.OptionBlockObject, 0, maxKudosPoints)
+	for points := 1; points <= maxKudosPoints; points++ {
+		options = append(options, slack.NewOptionBlockObject(strconv.Itoa(points), plainText(strconv.Itoa(points)), nil))
+	}
+	users := slack.NewOptionsSelectBlockElement(slack.OptTypeUser, nil, kudosInputAction).WithInitialUser(recipient)
+	points := slack.NewOptionsSelectBlockElement(slack.OptTypeStatic, nil, kudosInputAction, options...)
+	points.InitialOption = options[0]
+
+	return slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      kudosCallbackID,
+		Title:           plainText(messages.KudosTitle),
+		Submit:          plainText(messages.KudosSubmit),
+		PrivateMetadata: metadata,
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewInputBlock(kudosRecipientBlock, plainText(messages.KudosRecipient), nil, users),
+			slack.NewInputBlock(kudosPointsBlock, plainText(messages.KudosPoints), nil, points),
+			slack.NewInputBlock(kudosReasonBlock, plainText(messages.KudosReason), nil,
+				slack.NewPlainTextInputBlockElement(nil, kudosInputAction)).WithOptional(true),
+		}},
+	}
+}
+
+// kudosErrors checks a submission of the kudos modal, and returns the errors to show by block ID, if any
+func (b *Bot) kudosErrors(callback slack.InteractionCallback) map[string]string {
+	var message kudosMessage
+	if err := json.Unmarshal([]byte(callback.View.PrivateMetadata), &message); err != nil {
+		b.logger.Error("Error decoding kudos message", "error", err)
+		return nil
+	}
+	if !b.channelEnabled(b.channelSettings(message.Channel)) {
+		messages := (*catalog.Load()).Messages(b.localeFor(message.Channel, callback.User.ID))
+		return map[string]string{kudosRecipientBlock: messages.KudosChannelDisabled}
+	}
+	return nil
+}
+
+// giveKudos gives the points chosen in a submission of the kudos modal, like the giver sent
+// "<reason> <@recipient>++" in the channel of the message, and links the reply to the message
+func (b *Bot) giveKudos(callback slack.InteractionCallback) {
+	var message kudosMessage
+	if err := json.Unmarshal([]byte(callback.View.PrivateMetadata), &message); err != nil {
+		b.logger.Error("Error decoding kudos message", "error", err)
+		return
+	}
+	values := callback.View.State.Values
+	recipient := values[kudosRecipientBlock][kudosInputAction].SelectedUser
+	points, err := strconv.Atoi(values[kudosPointsBlock][kudosInputAction].SelectedOption.Value)
+	if err != nil || points < 1 || points > maxKudosPoints || recipient == "" {
+		b.logger.Error("Invalid kudos submission", "recipient", recipient, "points", points, "error", err)
+		return
+	}
+	reason := values[kudosReasonBlock][kudosInputAction].Value
+
+	permalink, err := b.api.GetPermalink(&slack.PermalinkParameters{Channel: message.Channel, Ts: message.TimeStamp})
+	if err != nil {
+		b.logger.Error("Error getting permalink", "error", err)
+	}
+	ev := &slackevents.MessageEvent{
+		User:            callback.User.ID,
+		Channel:         message.Channel,
+		TimeStamp:       message.TimeStamp,
+		ThreadTimeStamp: message.ThreadTimeStamp,
+		Text:            strings.TrimSpace(strings.Join(strings.Fields(reason), " ") + " <@" + recipient + ">++"),
+	}
+	b.logger.Info("Kudos given", "giver", ev.User, "recipient", recipient, "points", points, "channel", ev.Channel)
+	b.changePoints(ev, PointUp, recipient, true, points, permalink)
+}
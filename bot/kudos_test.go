@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// kudosSubmission creates a submission of the kudos modal for a message
+func kudosSubmission(t *testing.T, giver, channel, ts, recipient, points, reason string) slack.InteractionCallback {
+	t.Helper()
+	metadata, err := json.Marshal(kudosMessage{Channel: channel, TimeStamp: ts})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var callback slack.InteractionCallback
+	callback.Type = slack.InteractionTypeViewSubmission
+	callback.User.ID = giver
+	callback.View = slack.View{
+		CallbackID:      kudosCallbackID,
+		PrivateMetadata: string(metadata),
+		State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+			kudosRecipientBlock: {kudosInputAction: {SelectedUser: recipient}},
+			kudosPointsBlock:    {kudosInputAction: {SelectedOption: slack.OptionBlockObject{Value: points}}},
+			kudosReasonBlock:    {kudosInputAction: {Value: reason}},
+		}},
+	}
+	return callback
+}
+
+func TestKudosShortcut(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	var callback slack.InteractionCallback
+	callback.Type = slack.InteractionTypeMessageAction
+	callback.CallbackID = kudosCallbackID
+	callback.User.ID = "U1"
+	callback.Channel.ID = "general"
+	callback.Message.Timestamp = "1.000000"
+	sim.bot.handleKudosShortcut(callback)
+	if got := out.String(); got != "" {
+		t.Errorf("shortcut on a bot message = %q, want no modal", got)
+	}
+
+	callback.Message.User = "U2"
+	sim.bot.handleKudosShortcut(callback)
+	if got, want := out.String(), "[modal give_kudos]: Give kudos (Recipient, Points, Reason)\n"; got != want {
+		t.Errorf("shortcut = %q, want %q", got, want)
+	}
+}
+
+func TestGiveKudos(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	submission := kudosSubmission(t, "U1", "general", "1.000000", "U2", "3", " great  write-up ")
+	if errs := sim.bot.kudosErrors(submission); errs != nil {
+		t.Errorf("kudosErrors() = %v, want none", errs)
+	}
+	sim.bot.giveKudos(submission)
+
+	got := out.String()
+	if !strings.HasPrefix(got, "#general: ") || !strings.HasSuffix(got, "\nFor <https://simulator.slack.com/archives/general/p1000000|this message>\n") {
+		t.Errorf("reply = %q, want a reply in #general linking the message", got)
+	}
+	points, err := sim.bot.repo.GetPoints(ctx, "U2")
+	if err != nil {
+		t.Fatalf("GetPoints() error = %v", err)
+	}
+	if points != 3 {
+		t.Errorf("points = %d, want 3", points)
+	}
+	events, err := sim.bot.repo.ListPointEvents(ctx, "U2", time.Time{})
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Giver != "U1" || events[0].Delta != 3 || events[0].Reason != "great write-up" {
+		t.Errorf("events = %+v, want 3 points from U1 for great write-up", events)
+	}
+
+	WithChannelAllowlist(true)(sim.bot)
+	want := map[string]string{kudosRecipientBlock: "Points can't be given in this channel."}
+	if errs := sim.bot.kudosErrors(submission); !maps.Equal(errs, want) {
+		t.Errorf("kudosErrors() in a disabled channel = %v, want %v", errs, want)
+	}
+}
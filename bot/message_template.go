@@ -0,0 +1,223 @@
+package bot
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"text/template"
+)
+
+// MessageData is the data available to message templates, e.g. "{{.Target}} now has {{points .Points}}"
+type MessageData struct {
+	// Target is the target as it appears in Slack: "<@U123>" for users, ":sake:" for emoji
+	Target string
+	// TargetID is the user ID or emoji name of the target
+	TargetID string
+	// IsUser indicates whether the target is a user
+	IsUser bool
+	// Giver is the mention of the user who gave the points, or empty if unknown
+	Giver string
+	// GiverID is the user ID of the giver, or empty if unknown
+	GiverID string
+	// Delta is the change in points: 1 for ++, -1 for --, 0 for ==; in digests, the points received
+	// or given in the period
+	Delta int
+	// Points is the current (new) total of the target
+	Points int
+	// Previous is the total of the target before the change
+	Previous int
+	// Rank is the 1-based position of the target on the leaderboard
+	Rank int
+	// PreviousRank is the rank of the target at the start of the period, for the movers of digests
+	PreviousRank int
+	// Reason is the text sent along with the operation, e.g. "thanks for the review"
+	Reason string
+	// Milestone is the milestone the target has reached, for milestone messages
+	Milestone int
+	// Channel is the channel the points were given in, as a mention like "<#C123>"
+	Channel string
+	// Permalink is the link to the message the points were given in, or given for with the kudos shortcut, if known
+	Permalink string
+	// Given is how many times the giver gave the target points in reciprocal giving reports,
+	// the total of the points the target has given in stats, and how many times a reason was given in digests
+	Given int
+	// Returned is how many times the target gave the giver points back, in reciprocal giving reports
+	Returned int
+	// Recipients is the number of different targets the target has given points to, in stats
+	Recipients int
+	// Streak is the number of consecutive days the target has given points on, and LongestStreak
+	// the longest such run, in stats and streak_nudge
+	Streak        int
+	LongestStreak int
+	// LastGiven is when the target last gave points, as a Slack date, in stats; empty if never
+	LastGiven string
+	// Period is the days a digest, score or season covers, like "2025-05-12 – 2025-05-18"
+	Period string
+	// Season is the name of a season, in season messages
+	Season string
+	// Badge is the emoji and the name of a badge, and BadgeDescription what it is awarded for, in badge messages
+	Badge            string
+	BadgeDescription string
+	// Awarded is when a badge was awarded, as a Slack date, in badges_line
+	Awarded string
+	// Received is when points were received, as a Slack date, in home_recent_line
+	Received string
+
+	// pointsString formats a number of points in the message locale
+	pointsString func(int) string
+	// loadRank computes Rank; it is only called for templates that use it
+	loadRank func() int
+}
+
+// newMessageData creates the template data for a target
+func newMessageData(target string, isUser bool, points int) MessageData {
+	return MessageData{
+		Target:   formatTarget(target, isUser),
+		TargetID: target,
+		IsUser:   isUser,
+		Points:   points,
+		Previous: points,
+	}
+}
+
+// withGiver sets the giver of the points
+func (d MessageData) withGiver(giver string) MessageData {
+	if giver != "" {
+		d.Giver = formatTarget(giver, true)
+		d.GiverID = giver
+	}
+	return d
+}
+
+// formatTarget formats a target as it appears in Slack
+func formatTarget(target string, isUser bool) string {
+	if isUser {
+		return fmt.Sprintf("<@%s>", target)
+	}
+	return fmt.Sprintf(":%s:", target)
+}
+
+// legacyPlaceholders maps the placeholders of the original message format to template actions
+var legacyPlaceholders = strings.NewReplacer(
+	"{thing}", "{{.Target}}",
+	"{points_string}", "{{points .Points}}",
+)
+
+// templateActionPattern matches template actions like {{.Target}}
+var templateActionPattern = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// plural returns singular if n is 1 or -1, and plural otherwise
+func plural(n int, singular, plural string) string {
+	if n == 1 || n == -1 {
+		return singular
+	}
+	return plural
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// defaultPointsString formats a number of points when the data has no locale to format them in
+func defaultPointsString(n int) string {
+	return fmt.Sprintf("%d", n)
+}
+
+// parseMessageTemplate parses a message text, converting the legacy placeholders into template actions.
+// Only the functions below are available, so templates cannot reach anything but MessageData.
+// The points function is replaced with the one of the data when rendering.
+func parseMessageTemplate(text string) (*template.Template, error) {
+	return template.New("message").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"plural": plural,
+			"abs":    abs,
+			"points": defaultPointsString,
+		}).
+		Parse(legacyPlaceholders.Replace(text))
+}
+
+// parsedTemplates holds the templates of the texts of the catalog in use, keyed by text. They are parsed
+// once when the catalog is set, rather than every time a message is rendered.
+var parsedTemplates atomic.Pointer[map[string]*template.Template]
+
+// parseCatalogTemplates parses every text of a catalog. Texts that fail to parse are left out, and are
+// parsed again, and fall back, when rendered; catalogs are validated before they are set.
+func parseCatalogTemplates(c Catalog) map[string]*template.Template {
+	templates := map[string]*template.Template{}
+	add := func(text string) {
+		if _, ok := templates[text]; ok {
+			return
+		}
+		if tmpl, err := parseMessageTemplate(text); err == nil {
+			templates[text] = tmpl
+		}
+	}
+	for _, m := range c {
+		for _, field := range m.fields(reflect.Slice) {
+			for _, text := range field.value.Interface().([]string) {
+				add(text)
+			}
+		}
+		for _, field := range m.fields(reflect.String) {
+			add(field.value.String())
+		}
+	}
+	return templates
+}
+
+// messageTemplate returns the template of a message text, parsed when the catalog was set if the text is
+// part of it. The template is a copy, whose functions can be replaced.
+func messageTemplate(text string) (*template.Template, error) {
+	if templates := parsedTemplates.Load(); templates != nil {
+		if tmpl, ok := (*templates)[text]; ok {
+			return tmpl.Clone()
+		}
+	}
+	return parseMessageTemplate(text)
+}
+
+// renderMessage renders a message text with the given data. If the text cannot be rendered,
+// it is returned with only the legacy placeholders replaced.
+func renderMessage(text string, data MessageData) (string, error) {
+	pointsString := data.pointsString
+	if pointsString == nil {
+		pointsString = defaultPointsString
+	}
+
+	if data.loadRank != nil && strings.Contains(text, ".Rank") {
+		data.Rank = data.loadRank()
+	}
+
+	tmpl, err := messageTemplate(text)
+	if err == nil {
+		var b strings.Builder
+		if err = tmpl.Funcs(template.FuncMap{"points": pointsString}).Execute(&b, data); err == nil {
+			return b.String(), nil
+		}
+	}
+
+	fallback := strings.NewReplacer("{thing}", data.Target, "{points_string}", pointsString(data.Points)).Replace(text)
+	return fallback, err
+}
+
+// validateMessageTemplate checks that a message text only uses known placeholders
+// and is a valid template that renders with sample data
+func validateMessageTemplate(text string) error {
+	for _, placeholder := range placeholderPattern.FindAllString(templateActionPattern.ReplaceAllString(text, ""), -1) {
+		if !knownPlaceholders[placeholder] {
+			return fmt.Errorf("unknown placeholder %s", placeholder)
+		}
+	}
+
+	sample := newMessageData("U123456", true, 1).withGiver("U654321")
+	sample.loadRank = func() int { return 1 }
+	_, err := renderMessage(text, sample)
+	return err
+}
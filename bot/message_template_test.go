@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderMessage(t *testing.T) {
+	data := newMessageData("U123456", true, 5).withGiver("U654321")
+	data.Delta = 1
+	data.Previous = 4
+	data.Reason = "thanks for the review"
+	data.pointsString = defaultCatalog[DefaultLocale].formatPoints
+	data.loadRank = func() int { return 3 }
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"legacy placeholders", "{thing} now has {points_string}!", "<@U123456> now has 5 points!"},
+		{"fields", "{{.Giver}} gave {{.Target}} {{.Delta}} ({{.Previous}} -> {{.Points}})", "<@U654321> gave <@U123456> 1 (4 -> 5)"},
+		{"rank", "{{.Target}} is #{{.Rank}}", "<@U123456> is #3"},
+		{"reason", "{{if .Reason}}for {{.Reason}}{{end}}", "for thanks for the review"},
+		{"plural", "{{.Delta}} {{plural .Delta \"vote\" \"votes\"}}", "1 vote"},
+		{"points", "{{points .Delta}} and {{points .Points}}", "1 point and 5 points"},
+		{"abs", "{{abs -3}}", "3"},
+		{"mixed", "{thing}: {{.Previous}} -> {points_string}", "<@U123456>: 4 -> 5 points"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderMessage(tt.text, data)
+			if err != nil {
+				t.Fatalf("renderMessage() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("renderMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMessageRankIsLoadedOnDemand(t *testing.T) {
+	loaded := 0
+	data := newMessageData("sake", false, 1)
+	data.loadRank = func() int {
+		loaded++
+		return 1
+	}
+
+	if _, err := renderMessage("{thing} has {points_string}", data); err != nil {
+		t.Fatalf("renderMessage() error = %v", err)
+	}
+	if loaded != 0 {
+		t.Errorf("rank was loaded for a message that does not use it")
+	}
+	if _, err := renderMessage("{thing} is #{{.Rank}}", data); err != nil {
+		t.Fatalf("renderMessage() error = %v", err)
+	}
+	if loaded != 1 {
+		t.Errorf("rank was loaded %d times, want 1", loaded)
+	}
+}
+
+func TestRenderMessageFallback(t *testing.T) {
+	got, err := renderMessage("{thing} has {{.Missing}}", newMessageData("sake", false, 1))
+	if err == nil {
+		t.Fatal("renderMessage() error = nil, want an error for an unknown field")
+	}
+	if got != ":sake: has {{.Missing}}" {
+		t.Errorf("renderMessage() = %q, want the text with the legacy placeholders replaced", got)
+	}
+}
+
+func TestRenderMessageParsedWithCatalog(t *testing.T) {
+	SetCatalog(defaultCatalog)
+	text := defaultCatalog[DefaultLocale].Equals[0]
+	cached, ok := (*parsedTemplates.Load())[text]
+	if !ok {
+		t.Fatalf("template of %q was not parsed with the catalog", text)
+	}
+
+	// Each render uses the points function of its data without changing the shared template
+	data := newMessageData("sake", false, 5)
+	data.pointsString = func(n int) string { return fmt.Sprintf("%d pts", n) }
+	got, err := renderMessage(text, data)
+	if err != nil {
+		t.Fatalf("renderMessage() error = %v", err)
+	}
+	if !strings.Contains(got, "5 pts") {
+		t.Errorf("renderMessage() = %q, want the points formatted by the data", got)
+	}
+	if got, _ := renderMessage(text, newMessageData("sake", false, 5)); strings.Contains(got, "pts") {
+		t.Errorf("renderMessage() without a points function = %q, want the default format", got)
+	}
+	if (*parsedTemplates.Load())[text] != cached {
+		t.Error("rendering replaced the parsed template")
+	}
+}
+
+func TestValidateMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{"legacy", "{thing} has {points_string}", ""},
+		{"template", "{{.Target}} is #{{.Rank}} with {{points .Points}}", ""},
+		{"unknown placeholder", "{thing} has {score}", "unknown placeholder {score}"},
+		{"syntax error", "{{.Target", "unclosed action"},
+		{"unknown field", "{{.Score}}", "Score"},
+		{"unknown function", "{{printf \"%d\" .Points | shout}}", "shout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMessageTemplate(tt.text)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateMessageTemplate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateMessageTemplate() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtractReason(t *testing.T) {
+	tests := []struct {
+		text   string
+		isUser bool
+		want   string
+	}{
+		{"<@U123456>++", true, ""},
+		{"thanks for the review <@U123456> ++", true, "thanks for the review"},
+		{"<@U123456> ++\nfor fixing the build", true, "for fixing the build"},
+		{"good one :sake: --", false, "good one"},
+		{"great launch <!subteam^S123|@launch>++", true, "great launch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := extractReason(tt.text, tt.isUser); got != tt.want {
+				t.Errorf("extractReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
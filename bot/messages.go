@@ -1,17 +1,33 @@
 package bot
 
 import (
+	"bytes"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-//go:embed messages.json
+//go:embed messages*.json
 var messageFS embed.FS
 
+// DefaultLocale is the locale used when no message set exists for the requested one
+const DefaultLocale = "en"
+
 type Messages struct {
 	Plus        []string `json:"plus"`
 	PlusPoints  []string `json:"plus_points"`
@@ -19,6 +35,112 @@ type Messages struct {
 	MinusPoints []string `json:"minus_points"`
 	Equals      []string `json:"equals"`
 	Self        []string `json:"self"`
+	// Milestone and ShameMilestone are used when a target reaches a milestone or a shame milestone
+	Milestone      []string `json:"milestone"`
+	ShameMilestone []string `json:"shame_milestone"`
+	// MinusDisabled is used instead of a reply to -- when negative points are disabled
+	MinusDisabled []string `json:"minus_disabled"`
+
+	PointsString string `json:"points_string"`
+	// PointsStringOne is used instead of PointsString for 1 and -1 points, if set
+	PointsStringOne string `json:"points_string_one"`
+	// Context is the line below Block Kit replies naming the giver and the reason
+	Context string `json:"context"`
+	// LeaderboardTitle is the title of the leaderboard
+	LeaderboardTitle string `json:"leaderboard_title"`
+	// LeaderboardDecayedTitle is the title of the leaderboard when old points count less
+	LeaderboardDecayedTitle string `json:"leaderboard_decayed_title"`
+	// LeaderboardEmpty is the leaderboard reply when nobody has any points
+	LeaderboardEmpty string `json:"leaderboard_empty"`
+	// LeaderboardRow is a line of the plain text leaderboard
+	LeaderboardRow string `json:"leaderboard_row"`
+	// LeaderboardColumns are the rank, name and points column headers of the Block Kit leaderboard
+	LeaderboardColumns []string `json:"leaderboard_columns"`
+	// NotificationTitle and NotificationLine make up the direct message notifying a user of received points
+	NotificationTitle string `json:"notification_title"`
+	NotificationLine  string `json:"notification_line"`
+	// NotificationsOn and NotificationsOff are the replies to the notify command
+	NotificationsOn  string `json:"notifications_on"`
+	NotificationsOff string `json:"notifications_off"`
+	// ChannelSettingsTitle, ChannelSettingsUsage and NotBotManager are the replies to the channel command
+	ChannelSettingsTitle string `json:"channel_settings_title"`
+	ChannelSettingsUsage string `json:"channel_settings_usage"`
+	NotBotManager        string `json:"not_bot_manager"`
+	// CollusionTitle, CollusionLine and CollusionNone make up the reciprocal giving report
+	CollusionTitle string `json:"collusion_title"`
+	CollusionLine  string `json:"collusion_line"`
+	CollusionNone  string `json:"collusion_none"`
+	// NotAdmin is the reply to commands only workspace admins can use
+	NotAdmin string `json:"not_admin"`
+	// GiversTitle and GiversEmpty make up the leaderboard of givers
+	GiversTitle string `json:"givers_title"`
+	GiversEmpty string `json:"givers_empty"`
+	// Stats and StatsUsage are the replies to the stats command
+	Stats      string `json:"stats"`
+	StatsUsage string `json:"stats_usage"`
+	// DigestTitle, DigestEmpty and the section titles and lines make up the digest of a period
+	DigestTitle      string `json:"digest_title"`
+	DigestEmpty      string `json:"digest_empty"`
+	DigestRecipients string `json:"digest_recipients"`
+	DigestGivers     string `json:"digest_givers"`
+	DigestMovers     string `json:"digest_movers"`
+	DigestMilestones string `json:"digest_milestones"`
+	DigestReasons    string `json:"digest_reasons"`
+	DigestRow        string `json:"digest_row"`
+	DigestMover      string `json:"digest_mover"`
+	DigestMilestone  string `json:"digest_milestone"`
+	DigestReason     string `json:"digest_reason"`
+	// DigestUsage is the reply to the digest command with an unknown period
+	DigestUsage string `json:"digest_usage"`
+	// Score and ScoreUsage are the replies to the score command
+	Score      string `json:"score"`
+	ScoreUsage string `json:"score_usage"`
+	// SeasonTitle, SeasonFinalTitle and SeasonEmpty make up the standings of a season
+	SeasonTitle      string `json:"season_title"`
+	SeasonFinalTitle string `json:"season_final_title"`
+	SeasonEmpty      string `json:"season_empty"`
+	// SeasonNone, SeasonNotFound, SeasonInProgress, SeasonExists, SeasonStarted and SeasonUsage are the replies
+	// to the season command
+	SeasonNone       string `json:"season_none"`
+	SeasonNotFound   string `json:"season_not_found"`
+	SeasonInProgress string `json:"season_in_progress"`
+	SeasonExists     string `json:"season_exists"`
+	SeasonStarted    string `json:"season_started"`
+	SeasonUsage      string `json:"season_usage"`
+	// SeasonsTitle, SeasonsEmpty and the lines for ended seasons and the season in progress make up the list of seasons
+	SeasonsTitle   string `json:"seasons_title"`
+	SeasonsEmpty   string `json:"seasons_empty"`
+	SeasonsLine    string `json:"seasons_line"`
+	SeasonsCurrent string `json:"seasons_current"`
+	// BadgeAwarded is added to the reply to a point change when a user earns a badge
+	BadgeAwarded string `json:"badge_awarded"`
+	// BadgesTitle, BadgesLine, BadgesEmpty and BadgesUsage are the replies to the badges command
+	BadgesTitle string `json:"badges_title"`
+	BadgesLine  string `json:"badges_line"`
+	BadgesEmpty string `json:"badges_empty"`
+	BadgesUsage string `json:"badges_usage"`
+	// StreakNudge is the direct message reminding a user that their giving streak breaks unless they give points today
+	StreakNudge string `json:"streak_nudge"`
+	// HomeTitle, HomeRecentTitle, HomeRecentLine, HomeRecentEmpty, HomeGiversTitle, HomeGiversEmpty,
+	// HomeRecipientsTitle and HomeRecipientsEmpty are the texts of the App Home tab
+	HomeTitle           string `json:"home_title"`
+	HomeRecentTitle     string `json:"home_recent_title"`
+	HomeRecentLine      string `json:"home_recent_line"`
+	HomeRecentEmpty     string `json:"home_recent_empty"`
+	HomeGiversTitle     string `json:"home_givers_title"`
+	HomeGiversEmpty     string `json:"home_givers_empty"`
+	HomeRecipientsTitle string `json:"home_recipients_title"`
+	HomeRecipientsEmpty string `json:"home_recipients_empty"`
+	// KudosTitle, KudosSubmit, KudosRecipient, KudosPoints and KudosReason are the texts of the modal of the
+	// "Give kudos for this message" shortcut, and KudosChannelDisabled its error in channels the bot is disabled in
+	KudosTitle           string `json:"kudos_title"`
+	KudosSubmit          string `json:"kudos_submit"`
+	KudosRecipient       string `json:"kudos_recipient"`
+	KudosPoints          string `json:"kudos_points"`
+	KudosReason          string `json:"kudos_reason"`
+	KudosChannelDisabled string `json:"kudos_channel_disabled"`
+	// KudosLink is the line linking the reply to a point change made with the shortcut to the message it is for
+	KudosLink string `json:"kudos_link"`
 }
 
 type MessageType int
@@ -28,56 +150,374 @@ const (
 	MinusPointsMessage
 	EqualsMessage
 	SelfMessage
+	MilestoneMessage
+	ShameMilestoneMessage
+	MinusDisabledMessage
 )
 
+// messageTypes lists every MessageType
+var messageTypes = []MessageType{PlusPointsMessage, MinusPointsMessage, EqualsMessage, SelfMessage, MilestoneMessage, ShameMilestoneMessage, MinusDisabledMessage}
+
+// Catalog holds message sets keyed by locale, such as "en" or "ja"
+type Catalog map[string]*Messages
+
 var (
-	messages Messages
-	rnd      = rand.New(rand.NewSource(time.Now().UnixNano()))
+	// defaultCatalog holds the messages embedded in the binary
+	defaultCatalog Catalog
+	// catalog holds the messages currently used for replies
+	catalog atomic.Pointer[Catalog]
+	// rnd picks messages, and is shared by replies sent concurrently
+	rnd = newLockedRand(time.Now().UnixNano())
 )
 
+// lockedRand is a random source safe for concurrent use, which can be reseeded
+type lockedRand struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// newLockedRand creates a lockedRand with the given seed
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Intn returns a random number in [0, n)
+func (r *lockedRand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rnd.Intn(n)
+}
+
+// Seed resets the source to the given seed
+func (r *lockedRand) Seed(seed int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rnd = rand.New(rand.NewSource(seed))
+}
+
+// placeholderPattern matches placeholders like {thing} in message templates
+var placeholderPattern = regexp.MustCompile(`\{[a-zA-Z_]+\}`)
+
+// knownPlaceholders are the placeholders of the original message format, which are still supported
+var knownPlaceholders = map[string]bool{
+	"{thing}":         true,
+	"{points_string}": true,
+}
+
+// localePattern matches locales like "ja", "en-US" or "pt_BR"
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})?$`)
+
 func init() {
-	// Load messages from embedded JSON file
-	data, err := messageFS.ReadFile("messages.json")
+	// Load messages from embedded JSON files
+	files, err := fs.Glob(messageFS, "messages*.json")
 	if err != nil {
 		panic(err)
 	}
 
-	if err := json.Unmarshal(data, &messages); err != nil {
-		panic(err)
+	defaultCatalog = Catalog{}
+	for _, file := range files {
+		data, err := messageFS.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+
+		var m Messages
+		if err := json.Unmarshal(data, &m); err != nil {
+			panic(err)
+		}
+		defaultCatalog[localeFromFileName(file)] = &m
 	}
+	SetCatalog(defaultCatalog)
 }
 
-func getFormattedMessage(messageType MessageType, target string, points int, isUser bool) string {
-	var reaction, template string
+// SetCatalog replaces the messages used for replies, parsing their templates
+func SetCatalog(c Catalog) {
+	templates := parseCatalogTemplates(c)
+	parsedTemplates.Store(&templates)
+	catalog.Store(&c)
+}
+
+// NormalizeLocale converts a locale like "ja-JP" or "ja_JP" to the form used as catalog keys ("ja-jp")
+func NormalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// localeFromFileName returns the locale of a messages file: "messages.ja.json" is Japanese,
+// and a file name without a locale, like "messages.json", is the default locale
+func localeFromFileName(name string) string {
+	parts := strings.Split(strings.TrimSuffix(path.Base(filepath.ToSlash(name)), ".json"), ".")
+	if len(parts) > 1 && localePattern.MatchString(parts[len(parts)-1]) {
+		return NormalizeLocale(parts[len(parts)-1])
+	}
+	return DefaultLocale
+}
+
+// Messages returns the message set for a locale, falling back from a regional locale ("ja-jp")
+// to its language ("ja") and then to the default locale
+func (c Catalog) Messages(locale string) *Messages {
+	locale = NormalizeLocale(locale)
+	if m, ok := c[locale]; ok {
+		return m
+	}
+	if language, _, found := strings.Cut(locale, "-"); found {
+		if m, ok := c[language]; ok {
+			return m
+		}
+	}
+	return c[DefaultLocale]
+}
+
+// clone returns a deep copy of the catalog, so that decoding into it leaves the original untouched
+func (c Catalog) clone() Catalog {
+	clone := make(Catalog, len(c))
+	for locale, m := range c {
+		cloned := m.clone()
+		clone[locale] = &cloned
+	}
+	return clone
+}
+
+// Validate checks every message set of the catalog
+func (c Catalog) Validate() error {
+	if _, ok := c[DefaultLocale]; !ok {
+		return fmt.Errorf("no messages for the default locale %q", DefaultLocale)
+	}
+
+	locales := make([]string, 0, len(c))
+	for locale := range c {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	var errs []error
+	for _, locale := range locales {
+		if err := c[locale].Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", locale, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LoadCatalog loads messages from a JSON file, or from every JSON file in a directory in name order,
+// on top of the embedded defaults. The locale of a file is taken from its name ("custom.ja.json"),
+// and files without one apply to the default locale. Categories present in a file replace the ones
+// loaded before it, and categories missing from every file keep their defaults. A locale without
+// embedded defaults starts from the default locale's messages. The result is validated.
+func LoadCatalog(path string) (Catalog, error) {
+	files, err := messageFiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := defaultCatalog.clone()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		locale := localeFromFileName(file)
+		m, ok := c[locale]
+		if !ok {
+			base := c[DefaultLocale].clone()
+			m = &base
+			c[locale] = m
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(m); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid messages in %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// clone returns a deep copy of the messages, so that decoding into it leaves the original untouched
+func (m *Messages) clone() Messages {
+	c := *m
+	for _, field := range c.fields(reflect.Slice) {
+		field.value.Set(reflect.ValueOf(slices.Clone(field.value.Interface().([]string))))
+	}
+	return c
+}
+
+// messageField is a field of Messages with its JSON key
+type messageField struct {
+	name  string
+	value reflect.Value
+}
+
+// fields returns the fields of the given kind, string or slice, with their JSON keys in declaration order.
+// The values can be set.
+func (m *Messages) fields(kind reflect.Kind) []messageField {
+	v := reflect.ValueOf(m).Elem()
+	var fields []messageField
+	for i := range v.NumField() {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if name == "" || v.Field(i).Kind() != kind {
+			continue
+		}
+		fields = append(fields, messageField{name: name, value: v.Field(i)})
+	}
+	return fields
+}
+
+// messageFiles returns the message files at path: the file itself, or the JSON files in a directory in name order
+func messageFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no JSON files found in %s", path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Validate checks that every category has at least one message, that every message is a valid template,
+// and that only known placeholders are used
+func (m *Messages) Validate() error {
+	var errs []error
+
+	for _, field := range m.fields(reflect.Slice) {
+		if field.name == "leaderboard_columns" {
+			continue
+		}
+		texts := field.value.Interface().([]string)
+		if len(texts) == 0 {
+			errs = append(errs, fmt.Errorf("%s: no messages", field.name))
+		}
+		for i, text := range texts {
+			if strings.TrimSpace(text) == "" {
+				errs = append(errs, fmt.Errorf("%s[%d]: empty message", field.name, i))
+			}
+			if err := validateMessageTemplate(text); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: %w", field.name, i, err))
+			}
+		}
+	}
+
+	for _, field := range m.fields(reflect.String) {
+		if field.name == "points_string" || field.name == "points_string_one" {
+			// The points strings use {points} rather than templates, and are checked below
+			continue
+		}
+		text := field.value.String()
+		if strings.TrimSpace(text) == "" {
+			errs = append(errs, fmt.Errorf("%s: empty message", field.name))
+			continue
+		}
+		if err := validateMessageTemplate(text); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field.name, err))
+		}
+	}
+	if len(m.LeaderboardColumns) != 3 {
+		errs = append(errs, fmt.Errorf("leaderboard_columns: must have 3 columns, got %d", len(m.LeaderboardColumns)))
+	}
+
+	pointsStrings := []struct {
+		name     string
+		text     string
+		required bool
+	}{
+		{"points_string", m.PointsString, true},
+		{"points_string_one", m.PointsStringOne, false},
+	}
+	for _, p := range pointsStrings {
+		if p.text == "" && !p.required {
+			continue
+		}
+		if !strings.Contains(p.text, "{points}") {
+			errs = append(errs, fmt.Errorf("%s: must contain {points}", p.name))
+		}
+		for _, placeholder := range placeholderPattern.FindAllString(p.text, -1) {
+			if placeholder != "{points}" {
+				errs = append(errs, fmt.Errorf("%s: unknown placeholder %s", p.name, placeholder))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// seedMessages resets the random source used to pick messages so that replies are reproducible
+func seedMessages(seed int64) {
+	rnd.Seed(seed)
+}
+
+// texts returns the reactions and templates to pick from for a message type
+func (m *Messages) texts(messageType MessageType) ([]string, []string) {
 	switch messageType {
 	case PlusPointsMessage:
-		reaction = messages.Plus[rnd.Intn(len(messages.Plus))]
-		template = messages.PlusPoints[rnd.Intn(len(messages.PlusPoints))]
+		return m.Plus, m.PlusPoints
 	case MinusPointsMessage:
-		reaction = messages.Minus[rnd.Intn(len(messages.Minus))]
-		template = messages.MinusPoints[rnd.Intn(len(messages.MinusPoints))]
+		return m.Minus, m.MinusPoints
 	case EqualsMessage:
-		reaction = ""
-		template = messages.Equals[rnd.Intn(len(messages.Equals))]
+		return nil, m.Equals
 	case SelfMessage:
-		reaction = ""
-		template = messages.Self[rnd.Intn(len(messages.Self))]
+		return nil, m.Self
+	case MilestoneMessage:
+		return nil, m.Milestone
+	case ShameMilestoneMessage:
+		return nil, m.ShameMilestone
+	case MinusDisabledMessage:
+		return nil, m.MinusDisabled
 	}
+	return nil, nil
+}
 
-	pointsStr := fmt.Sprintf("%d points", points)
-	
-	// Format target based on whether it's a user or emoji
-	var targetStr string
-	if isUser {
-		targetStr = fmt.Sprintf("<@%s>", target)
-	} else {
-		targetStr = fmt.Sprintf(":%s:", target)
+// formatPoints formats a number of points, like "5 points" or "1 point"
+func (m *Messages) formatPoints(points int) string {
+	format := m.PointsString
+	if m.PointsStringOne != "" && (points == 1 || points == -1) {
+		format = m.PointsStringOne
 	}
+	return strings.ReplaceAll(format, "{points}", fmt.Sprintf("%d", points))
+}
 
-	message := strings.ReplaceAll(template, "{thing}", targetStr)
-	message = strings.ReplaceAll(message, "{points_string}", pointsStr)
+// getFormattedMessage formats a message in the default locale
+func getFormattedMessage(messageType MessageType, target string, points int, isUser bool) string {
+	return formatMessage(DefaultLocale, messageType, newMessageData(target, isUser, points))
+}
+
+// formatMessage formats a randomly picked message of the given type in the given locale
+func formatMessage(locale string, messageType MessageType, data MessageData) string {
+	messages := (*catalog.Load()).Messages(locale)
+	data.pointsString = messages.formatPoints
+
+	var reaction, template string
+	reactions, templates := messages.texts(messageType)
+	if len(reactions) > 0 {
+		reaction = reactions[rnd.Intn(len(reactions))]
+	}
+	template = templates[rnd.Intn(len(templates))]
+
+	message := renderText(template, data)
 	if reaction != "" {
-		message = fmt.Sprintf("%s %s", reaction, message)
+		message = fmt.Sprintf("%s %s", renderText(reaction, data), message)
+	}
+	return message
+}
+
+// renderText renders a message text, logging templates that fail to render
+func renderText(text string, data MessageData) string {
+	message, err := renderMessage(text, data)
+	if err != nil {
+		slog.Error("Error rendering message template", "template", text, "error", err)
 	}
 	return message
 }
@@ -1,8 +1,17 @@
 package bot
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"log/slog"
 )
 
 func TestGetFormattedMessage(t *testing.T) {
@@ -115,3 +124,233 @@ func TestGetFormattedMessage(t *testing.T) {
 		})
 	}
 }
+
+func writeMessagesFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write messages file: %v", err)
+	}
+	return path
+}
+
+func TestLoadCatalog(t *testing.T) {
+	t.Run("file overrides categories", func(t *testing.T) {
+		path := writeMessagesFile(t, t.TempDir(), "messages.json", `{"plus": ["Hooray!"]}`)
+
+		c, err := LoadCatalog(path)
+		if err != nil {
+			t.Fatalf("LoadCatalog() error = %v", err)
+		}
+		m := c[DefaultLocale]
+		if len(m.Plus) != 1 || m.Plus[0] != "Hooray!" {
+			t.Errorf("LoadCatalog() Plus = %v, want [Hooray!]", m.Plus)
+		}
+		if len(m.Minus) != len(defaultCatalog[DefaultLocale].Minus) {
+			t.Errorf("LoadCatalog() Minus = %v, want the defaults", m.Minus)
+		}
+		if defaultCatalog[DefaultLocale].Plus[0] == "Hooray!" {
+			t.Error("LoadCatalog() modified the embedded messages")
+		}
+	})
+
+	t.Run("locale is taken from the file name", func(t *testing.T) {
+		dir := t.TempDir()
+		writeMessagesFile(t, dir, "custom.ja.json", `{"plus": ["いいね！"]}`)
+		writeMessagesFile(t, dir, "custom.fr.json", `{"plus": ["Bravo !"], "points_string": "{points} points"}`)
+
+		c, err := LoadCatalog(dir)
+		if err != nil {
+			t.Fatalf("LoadCatalog() error = %v", err)
+		}
+		if c["ja"].Plus[0] != "いいね！" || c["ja"].Equals[0] != defaultCatalog["ja"].Equals[0] {
+			t.Errorf("LoadCatalog() ja = %+v, want the override on top of the Japanese defaults", c["ja"])
+		}
+		if c["fr"].Plus[0] != "Bravo !" || c["fr"].Equals[0] != defaultCatalog[DefaultLocale].Equals[0] {
+			t.Errorf("LoadCatalog() fr = %+v, want the override on top of the English defaults", c["fr"])
+		}
+	})
+
+	t.Run("directory files are applied in name order", func(t *testing.T) {
+		dir := t.TempDir()
+		writeMessagesFile(t, dir, "10-base.json", `{"plus": ["First"], "equals": ["{thing} has {points_string}"]}`)
+		writeMessagesFile(t, dir, "20-override.json", `{"plus": ["Second"]}`)
+		writeMessagesFile(t, dir, "README.md", `not a message file`)
+
+		c, err := LoadCatalog(dir)
+		if err != nil {
+			t.Fatalf("LoadCatalog() error = %v", err)
+		}
+		m := c[DefaultLocale]
+		if m.Plus[0] != "Second" || m.Equals[0] != "{thing} has {points_string}" {
+			t.Errorf("LoadCatalog() = %+v", m)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"empty category", `{"self": []}`, "self: no messages"},
+		{"unknown placeholder", `{"plus_points": ["{thing} has {score}"]}`, "unknown placeholder {score}"},
+		{"unknown category", `{"plsu": ["Yay"]}`, "plsu"},
+		{"invalid JSON", `{"plus": [`, "failed to parse"},
+		{"points string without points", `{"points_string": "many points"}`, "points_string: must contain {points}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeMessagesFile(t, t.TempDir(), "messages.json", tt.content)
+			_, err := LoadCatalog(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadCatalog() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEmbeddedCatalog(t *testing.T) {
+	if err := defaultCatalog.Validate(); err != nil {
+		t.Errorf("embedded messages are invalid: %v", err)
+	}
+
+	for _, locale := range []string{"en", "ja"} {
+		m, ok := defaultCatalog[locale]
+		if !ok {
+			t.Errorf("no embedded messages for locale %q", locale)
+			continue
+		}
+		for _, messageType := range messageTypes {
+			if _, templates := m.texts(messageType); len(templates) == 0 {
+				t.Errorf("locale %q has no messages for message type %d", locale, messageType)
+			}
+		}
+	}
+}
+
+func TestMessagesClone(t *testing.T) {
+	original := defaultCatalog[DefaultLocale]
+	cloned := original.clone()
+	if !reflect.DeepEqual(cloned, *original) {
+		t.Fatalf("clone() = %+v, want a copy of %+v", cloned, *original)
+	}
+
+	// Every slice is copied, so changing the clone leaves the original untouched
+	for _, field := range cloned.fields(reflect.Slice) {
+		texts := field.value.Interface().([]string)
+		if len(texts) == 0 {
+			continue
+		}
+		texts[0] = "changed"
+	}
+	for _, field := range original.fields(reflect.Slice) {
+		if slices.Contains(field.value.Interface().([]string), "changed") {
+			t.Errorf("changing %s of the clone changed the original", field.name)
+		}
+	}
+}
+
+func TestMessagesValidateEveryText(t *testing.T) {
+	// Every text is validated without being listed, including the most recently added ones
+	m := defaultCatalog[DefaultLocale].clone()
+	m.KudosLink = ""
+	m.HomeTitle = "{{.Unknown}}"
+	err := m.Validate()
+	if err == nil {
+		t.Fatal("Validate() error = nil, want errors")
+	}
+	for _, want := range []string{"kudos_link: empty message", "home_title: "} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error = %v, want to contain %q", err, want)
+		}
+	}
+}
+
+func TestCatalogMessages(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"en", "en"},
+		{"ja", "ja"},
+		{"ja-JP", "ja"},
+		{"ja_JP", "ja"},
+		{"en-US", "en"},
+		{"fr-FR", "en"},
+		{"", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			if got := defaultCatalog.Messages(tt.locale); got != defaultCatalog[tt.want] {
+				t.Errorf("Messages(%q) did not return the %q messages", tt.locale, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatMessageLocalized(t *testing.T) {
+	got := formatMessage("ja-JP", EqualsMessage, newMessageData("U123456", true, 5))
+	if got != "<@U123456> は現在 5ポイント です。" {
+		t.Errorf("formatMessage() = %q", got)
+	}
+
+	got = formatMessage("en", EqualsMessage, newMessageData("U123456", true, 5))
+	if got != "<@U123456> is currently at 5 points." {
+		t.Errorf("formatMessage() = %q", got)
+	}
+}
+
+func TestFormatMessageConcurrent(t *testing.T) {
+	// Run with -race: replies are formatted concurrently while the simulator reseeds the source
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			formatMessage("en", PlusPointsMessage, newMessageData("U123456", true, 5))
+		}()
+		go func(seed int64) {
+			defer wg.Done()
+			seedMessages(seed)
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+func TestWatchMessages(t *testing.T) {
+	defer SetCatalog(defaultCatalog)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	dir := t.TempDir()
+	path := writeMessagesFile(t, dir, "messages.json", `{"equals": ["{thing} is at {points_string}."]}`)
+	c, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCatalog() error = %v", err)
+	}
+	SetCatalog(c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchMessages(ctx, path, 10*time.Millisecond, logger)
+
+	// An invalid file is ignored
+	time.Sleep(20 * time.Millisecond)
+	writeMessagesFile(t, dir, "messages.json", `{"equals": []}`)
+	time.Sleep(50 * time.Millisecond)
+	if got := getFormattedMessage(EqualsMessage, "sake", 1, false); got != ":sake: is at 1 point." {
+		t.Errorf("getFormattedMessage() = %q after an invalid change, want the previous message", got)
+	}
+
+	writeMessagesFile(t, dir, "messages.json", `{"equals": ["{thing} has {points_string} now."]}`)
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if getFormattedMessage(EqualsMessage, "sake", 1, false) == ":sake: has 1 point now." {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("messages were not reloaded after the file changed")
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// messagesFingerprint summarizes the names, sizes and modification times of the message files at path
+func messagesFingerprint(path string) (string, error) {
+	files, err := messageFiles(path)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// WatchMessages reloads the messages at path on SIGHUP, and whenever its files change when interval
+// is positive, until ctx is done. Messages that fail to load or validate are logged and the
+// previous messages are kept.
+func WatchMessages(ctx context.Context, path string, interval time.Duration, logger *slog.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	fingerprint, _ := messagesFingerprint(path)
+	reload := func(reason string) {
+		m, err := LoadCatalog(path)
+		if err != nil {
+			logger.Error("Failed to reload messages; keeping the previous messages", "path", path, "error", err)
+			return
+		}
+		SetCatalog(m)
+		logger.Info("Messages reloaded", "path", path, "reason", reason)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			fingerprint, _ = messagesFingerprint(path)
+			reload("SIGHUP")
+		case <-tick:
+			current, err := messagesFingerprint(path)
+			if err != nil || current == fingerprint {
+				continue
+			}
+			fingerprint = current
+			reload("file change")
+		}
+	}
+}
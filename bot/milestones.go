@@ -0,0 +1,41 @@
+package bot
+
+import "github.com/slack-go/slack/slackevents"
+
+// crossedMilestone returns the milestone passed when a total changes from previous to current:
+// a positive milestone is reached when the total rises to or above it, and a shame milestone
+// when the total falls to or below it. If several are passed at once, the furthest one is returned.
+func crossedMilestone(previous, current int, milestones, shameMilestones []int) (int, MessageType, bool) {
+	found := false
+	var milestone int
+	var messageType MessageType
+
+	for _, m := range milestones {
+		if previous < m && m <= current && (!found || m > milestone) {
+			milestone, messageType, found = m, MilestoneMessage, true
+		}
+	}
+	for _, m := range shameMilestones {
+		if current <= m && m < previous && (!found || m < milestone) {
+			milestone, messageType, found = m, ShameMilestoneMessage, true
+		}
+	}
+	return milestone, messageType, found
+}
+
+// celebrateMilestone posts a milestone message when a point change makes the target reach a milestone,
+// responding where the points were given and announcing it in the milestone channel if one is set
+func (b *Bot) celebrateMilestone(ev *slackevents.MessageEvent, locale, recipient string, data MessageData) {
+	milestone, messageType, ok := crossedMilestone(data.Previous, data.Points, b.milestones, b.shameMilestones)
+	if !ok {
+		return
+	}
+
+	b.logger.Info("Milestone reached", "target", data.TargetID, "milestone", milestone)
+	data.Milestone = milestone
+	r := pointsReply(locale, messageType, data)
+	b.respond(ev, messageType, recipient, r)
+	if b.milestoneChannel != "" && b.milestoneChannel != ev.Channel {
+		b.postReply(b.milestoneChannel, "", r)
+	}
+}
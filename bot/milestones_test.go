@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCrossedMilestone(t *testing.T) {
+	milestones := []int{10, 100}
+	shame := []int{-10}
+
+	tests := []struct {
+		name      string
+		previous  int
+		current   int
+		want      int
+		wantType  MessageType
+		wantFound bool
+	}{
+		{"reached", 9, 10, 10, MilestoneMessage, true},
+		{"passed several", 5, 150, 100, MilestoneMessage, true},
+		{"already above", 10, 11, 0, 0, false},
+		{"going down through a milestone", 10, 9, 0, 0, false},
+		{"shame reached", -9, -10, -10, ShameMilestoneMessage, true},
+		{"recovering from shame", -10, -9, 0, 0, false},
+		{"nothing", 0, 1, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotType, found := crossedMilestone(tt.previous, tt.current, milestones, shame)
+			if got != tt.want || gotType != tt.wantType || found != tt.wantFound {
+				t.Errorf("crossedMilestone(%d, %d) = %d, %v, %v, want %d, %v, %v", tt.previous, tt.current, got, gotType, found, tt.want, tt.wantType, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestSimulatorMilestones(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithMilestones([]int{2}, []int{-1})(sim.bot)
+	WithMilestoneChannel("kudos")(sim.bot)
+
+	script := "U1 #general: <@U2>++\nU1 #general: <@U2>++\nU1 #general: :sake:--\n"
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("output has %d lines, want 7: %q", len(lines), out.String())
+	}
+	if !strings.HasPrefix(lines[2], "#general: ") || !strings.Contains(lines[2], "<@U2>") || !strings.Contains(lines[2], "2 points") {
+		t.Errorf("milestone reply = %q", lines[2])
+	}
+	if lines[3] != strings.Replace(lines[2], "#general", "#kudos", 1) {
+		t.Errorf("milestone announcement = %q, want the milestone message in #kudos", lines[3])
+	}
+	if !strings.Contains(lines[5], ":sake:") || !strings.Contains(lines[5], "-1 point") || !strings.HasPrefix(lines[6], "#kudos: ") {
+		t.Errorf("shame milestone = %q, %q", lines[5], lines[6])
+	}
+}
@@ -0,0 +1,137 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// notification is a point change reported to its recipient in a direct message
+type notification struct {
+	giver     string
+	channel   string
+	timestamp string
+	delta     int
+	reason    string
+}
+
+// notifier batches notifications per recipient, so that a burst of point changes becomes one direct message.
+// The first notification for a recipient starts a timer, and everything queued until it fires is sent together.
+type notifier struct {
+	mu      sync.Mutex
+	delay   time.Duration
+	timers  bool
+	pending map[string][]notification
+	send    func(recipient string, notifications []notification)
+}
+
+// newNotifier creates a notifier that calls send with the batched notifications. Without timers,
+// queued notifications are only sent by flushAll.
+func newNotifier(delay time.Duration, timers bool, send func(recipient string, notifications []notification)) *notifier {
+	return &notifier{
+		delay:   delay,
+		timers:  timers,
+		pending: map[string][]notification{},
+		send:    send,
+	}
+}
+
+// add queues a notification for a recipient
+func (n *notifier) add(recipient string, item notification) {
+	n.mu.Lock()
+	first := len(n.pending[recipient]) == 0
+	n.pending[recipient] = append(n.pending[recipient], item)
+	n.mu.Unlock()
+
+	if !first {
+		return
+	}
+	if n.delay <= 0 {
+		n.flush(recipient)
+	} else if n.timers {
+		time.AfterFunc(n.delay, func() { n.flush(recipient) })
+	}
+}
+
+// flush sends the notifications queued for a recipient
+func (n *notifier) flush(recipient string) {
+	n.mu.Lock()
+	items := n.pending[recipient]
+	delete(n.pending, recipient)
+	n.mu.Unlock()
+
+	if len(items) > 0 {
+		n.send(recipient, items)
+	}
+}
+
+// flushAll sends every queued notification
+func (n *notifier) flushAll() {
+	n.mu.Lock()
+	recipients := make([]string, 0, len(n.pending))
+	for recipient := range n.pending {
+		recipients = append(recipients, recipient)
+	}
+	n.mu.Unlock()
+
+	for _, recipient := range recipients {
+		n.flush(recipient)
+	}
+}
+
+// notifyRecipient queues a direct message about a point change if the recipient has opted in,
+// and reports whether it did
+func (b *Bot) notifyRecipient(ev *slackevents.MessageEvent, recipient string, delta int, reason string) bool {
+	settings, err := b.repo.GetUserSettings(context.Background(), recipient)
+	if err != nil {
+		b.logger.Error("Error getting user settings", "error", err)
+		return false
+	}
+	if !settings.Notify {
+		return false
+	}
+
+	b.notifications.add(recipient, notification{
+		giver:     ev.User,
+		channel:   ev.Channel,
+		timestamp: ev.TimeStamp,
+		delta:     delta,
+		reason:    reason,
+	})
+	return true
+}
+
+// sendNotifications sends a recipient one direct message summarizing who gave them points, where and why
+func (b *Bot) sendNotifications(recipient string, items []notification) {
+	messages := (*catalog.Load()).Messages(b.localeFor("", recipient))
+
+	points, err := b.repo.GetPoints(context.Background(), recipient)
+	if err != nil {
+		b.logger.Error("Error getting points", "error", err)
+		return
+	}
+
+	data := newMessageData(recipient, true, points)
+	data.pointsString = messages.formatPoints
+	lines := []string{renderText(messages.NotificationTitle, data)}
+	for _, item := range items {
+		itemData := data.withGiver(item.giver)
+		itemData.Delta = item.delta
+		itemData.Reason = item.reason
+		itemData.Channel = "<#" + item.channel + ">"
+
+		permalink, err := b.api.GetPermalink(&slack.PermalinkParameters{Channel: item.channel, Ts: item.timestamp})
+		if err != nil {
+			b.logger.Error("Error getting permalink", "error", err)
+		}
+		itemData.Permalink = permalink
+
+		lines = append(lines, "• "+renderText(messages.NotificationLine, itemData))
+	}
+
+	b.postReply(recipient, "", reply{text: strings.Join(lines, "\n")})
+}
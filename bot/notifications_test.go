@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifierBatches(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string][]notification{}
+	done := make(chan struct{}, 2)
+	n := newNotifier(20*time.Millisecond, true, func(recipient string, items []notification) {
+		mu.Lock()
+		sent[recipient] = append(sent[recipient], items...)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+
+	n.add("U1", notification{giver: "U2", delta: 1})
+	n.add("U1", notification{giver: "U3", delta: 1})
+	n.add("U4", notification{giver: "U2", delta: -1})
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("notifications were not sent")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent["U1"]) != 2 || len(sent["U4"]) != 1 {
+		t.Errorf("sent = %+v, want two notifications for U1 and one for U4", sent)
+	}
+}
+
+func TestNotifierWithoutDelay(t *testing.T) {
+	calls := 0
+	n := newNotifier(0, false, func(recipient string, items []notification) {
+		calls++
+	})
+
+	n.add("U1", notification{giver: "U2", delta: 1})
+	n.add("U1", notification{giver: "U3", delta: 1})
+	if calls != 2 {
+		t.Errorf("send was called %d times, want once per notification", calls)
+	}
+}
+
+func TestSimulatorNotifications(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	sim.bot.notifications.delay = time.Minute
+
+	script := strings.Join([]string{
+		"U2 #general: <@UBOT> notify on",
+		"U1 #general: thanks for the review <@U2>++",
+		"U3 #random: <@U2>++",
+		"U1 #general: <@U4>++",
+	}, "\n")
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "#general: Direct message notifications are on: I'll let you know when you receive points." {
+		t.Errorf("notify reply = %q", lines[0])
+	}
+
+	var dm []string
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@U2: ") {
+			dm = lines[i:]
+			break
+		}
+	}
+	want := []string{
+		"@U2: You received points! You now have 2 points.",
+		"• <@U1> gave you 1 point in <#general>: thanks for the review (<https://simulator.slack.com/archives/general/p3000000|view message>)",
+		"• <@U3> gave you 1 point in <#random> (<https://simulator.slack.com/archives/random/p5000000|view message>)",
+	}
+	if strings.Join(dm, "\n") != strings.Join(want, "\n") {
+		t.Errorf("direct message = %q, want %q", dm, want)
+	}
+	if strings.Contains(out.String(), "\n@U4") {
+		t.Errorf("U4 was notified without opting in: %q", out.String())
+	}
+}
+
+func TestNotifyCommandStatus(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	for _, line := range []string{"U1 #general: <@UBOT> notify", "U1 #general: <@UBOT> notify on", "U1 #general: <@UBOT> notify OFF"} {
+		if err := sim.HandleLine(line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+	}
+
+	want := "#general: Direct message notifications are off.\n" +
+		"#general: Direct message notifications are on: I'll let you know when you receive points.\n" +
+		"#general: Direct message notifications are off.\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Period is a calendar period points are summarized over
+type Period string
+
+const (
+	// PeriodDay is a day starting at midnight
+	PeriodDay Period = "day"
+	// PeriodWeek is a week starting on Monday
+	PeriodWeek Period = "week"
+	// PeriodMonth is a calendar month
+	PeriodMonth Period = "month"
+	// PeriodQuarter is a calendar quarter starting in January, April, July or October
+	PeriodQuarter Period = "quarter"
+)
+
+// ParsePeriod parses a period name
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(strings.ToLower(s)); p {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodQuarter:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown period: %s", s)
+	}
+}
+
+// start returns the start of the period containing t, in the location of t
+func (p Period) start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch p {
+	case PeriodWeek:
+		// Weekday counts from Sunday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case PeriodMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	case PeriodQuarter:
+		return time.Date(day.Year(), (day.Month()-1)/3*3+1, 1, 0, 0, 0, 0, day.Location())
+	default:
+		return day
+	}
+}
+
+// end returns the end of the period starting at start
+func (p Period) end(start time.Time) time.Time {
+	switch p {
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case PeriodMonth:
+		return start.AddDate(0, 1, 0)
+	case PeriodQuarter:
+		return start.AddDate(0, 3, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// previous returns the start of the period before the one starting at start
+func (p Period) previous(start time.Time) time.Time {
+	return p.start(start.AddDate(0, 0, -1))
+}
+
+// formatPeriod formats the days from start to end, excluding end, like "2025-05-12 – 2025-05-18"
+func formatPeriod(start, end time.Time) string {
+	last := end.AddDate(0, 0, -1)
+	if !last.After(start) {
+		return start.Format(time.DateOnly)
+	}
+	return start.Format(time.DateOnly) + " – " + last.Format(time.DateOnly)
+}
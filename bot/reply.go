@@ -0,0 +1,155 @@
+package bot
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack"
+)
+
+// userIDPattern matches Slack user IDs. Bots are stored as non-users but are still shown as mentions.
+var userIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]{2,}$`)
+
+// isUserTarget reports whether a stored target is shown as a user mention rather than an emoji
+func isUserTarget(record repository.UserPoints) bool {
+	return record.IsUser || userIDPattern.MatchString(record.UserID)
+}
+
+// reply is a message posted by the bot: plain text, and Block Kit blocks used instead when rich replies are enabled
+type reply struct {
+	text   string
+	blocks []slack.Block
+}
+
+// postReply posts a reply to a channel, in the thread of threadTS if it is set
+func (b *Bot) postReply(channel, threadTS string, r reply) {
+	options := []slack.MsgOption{
+		slack.MsgOptionText(r.text, false),
+		slack.MsgOptionTS(threadTS),
+	}
+	if b.richReplies && len(r.blocks) > 0 {
+		// The text is still sent as the fallback for notifications and clients without Block Kit
+		options = append(options, slack.MsgOptionBlocks(r.blocks...))
+	}
+
+	if _, _, err := b.api.PostMessage(channel, options...); err != nil {
+		b.logger.Error("Error sending message", "error", err)
+		return
+	}
+	b.logger.Debug("Reply sent", "message", r.text)
+}
+
+// pointsReply creates the reply to a point operation: a section with the message,
+// followed by a context line naming the giver and the reason when points were given
+func pointsReply(locale string, messageType MessageType, data MessageData) reply {
+	text := formatMessage(locale, messageType, data)
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+	}
+	if data.Giver != "" && (messageType == PlusPointsMessage || messageType == MinusPointsMessage) {
+		context := renderText((*catalog.Load()).Messages(locale).Context, data)
+		blocks = append(blocks, slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, context, false, false)))
+	}
+	return reply{text: text, blocks: blocks}
+}
+
+// withLines returns the reply with lines added to its text, and as a section of its blocks
+func (r reply) withLines(lines []string) reply {
+	if len(lines) == 0 {
+		return r
+	}
+	text := strings.Join(lines, "\n")
+	r.text += "\n" + text
+	if r.blocks != nil {
+		r.blocks = append(slices.Clip(r.blocks), slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil))
+	}
+	return r
+}
+
+// combineReplies joins replies into one, one after another
+func combineReplies(replies []reply) reply {
+	var combined reply
+	texts := make([]string, 0, len(replies))
+	for _, r := range replies {
+		texts = append(texts, r.text)
+		combined.blocks = append(combined.blocks, r.blocks...)
+	}
+	combined.text = strings.Join(texts, "\n")
+	return combined
+}
+
+// leaderboardReply creates the leaderboard reply: a numbered list, or a table with rich replies.
+// Targets with equal points share a rank.
+func leaderboardReply(locale string, records []repository.UserPoints) reply {
+	messages := (*catalog.Load()).Messages(locale)
+	return rankingReply(messages, messages.LeaderboardTitle, messages.LeaderboardEmpty, records)
+}
+
+// giversReply creates the leaderboard of the users who gave the most points
+func giversReply(locale string, givers []repository.GiverStats) reply {
+	messages := (*catalog.Load()).Messages(locale)
+	records := make([]repository.UserPoints, 0, len(givers))
+	for _, giver := range givers {
+		records = append(records, repository.UserPoints{UserID: giver.UserID, Points: giver.Given, IsUser: true})
+	}
+	return rankingReply(messages, messages.GiversTitle, messages.GiversEmpty, records)
+}
+
+// rankingReply creates a ranking of records with the given title, or the empty text if there are none
+func rankingReply(messages *Messages, title, empty string, records []repository.UserPoints) reply {
+	if len(records) == 0 {
+		return reply{text: empty}
+	}
+
+	lines := []string{"*" + title + "*"}
+	table := slack.NewTableBlock("")
+	table.WithColumnSettings(
+		slack.ColumnSetting{Align: slack.ColumnAlignmentRight},
+		slack.ColumnSetting{Align: slack.ColumnAlignmentLeft},
+		slack.ColumnSetting{Align: slack.ColumnAlignmentRight},
+	)
+	header := make([]slack.TableCell, 0, len(messages.LeaderboardColumns))
+	for _, column := range messages.LeaderboardColumns {
+		header = append(header, slack.NewTableRichTextCell(slack.NewRichTextSection(
+			slack.NewRichTextSectionTextElement(column, &slack.RichTextSectionTextStyle{Bold: true}),
+		)))
+	}
+	table.AddRow(header...)
+
+	rank := 0
+	for i, record := range records {
+		if i == 0 || record.Points != records[i-1].Points {
+			rank = i + 1
+		}
+
+		isUser := isUserTarget(record)
+		data := newMessageData(record.UserID, isUser, record.Points)
+		data.Rank = rank
+		data.pointsString = messages.formatPoints
+		lines = append(lines, renderText(messages.LeaderboardRow, data))
+
+		table.AddRow(
+			slack.NewTableRawTextCell(strconv.Itoa(rank)),
+			slack.NewTableRichTextCell(slack.NewRichTextSection(targetElement(record.UserID, isUser))),
+			slack.NewTableRawTextCell(strconv.Itoa(record.Points)),
+		)
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, title, false, false)),
+		table,
+	}
+	return reply{text: strings.Join(lines, "\n"), blocks: blocks}
+}
+
+// targetElement returns the rich text element showing a target: a user mention or an emoji
+func targetElement(target string, isUser bool) slack.RichTextSectionElement {
+	if isUser {
+		return slack.NewRichTextSectionUserElement(target, nil)
+	}
+	return slack.NewRichTextSectionEmojiElement(target, 0, nil)
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// ReplyMode is how the bot responds to point changes in a channel
+type ReplyMode string
+
+const (
+	// ReplyInChannel replies in the channel, or in the thread if the message was sent in one
+	ReplyInChannel ReplyMode = "channel"
+	// ReplyInThread always replies in a thread of the message
+	ReplyInThread ReplyMode = "thread"
+	// ReplyWithReaction only adds an emoji reaction to the message
+	ReplyWithReaction ReplyMode = "reaction"
+	// ReplySilently does not reply in the channel, and sends the reply to the recipient as a direct message
+	ReplySilently ReplyMode = "silent"
+)
+
+// ParseReplyMode parses a reply mode name
+func ParseReplyMode(s string) (ReplyMode, error) {
+	switch mode := ReplyMode(s); mode {
+	case ReplyInChannel, ReplyInThread, ReplyWithReaction, ReplySilently:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown reply mode %q", s)
+}
+
+// reactionNames are the emoji added to messages in the reaction reply mode
+var reactionNames = map[MessageType]string{
+	PlusPointsMessage:     "heavy_plus_sign",
+	MinusPointsMessage:    "heavy_minus_sign",
+	SelfMessage:           "no_entry_sign",
+	MilestoneMessage:      "trophy",
+	ShameMilestoneMessage: "skull",
+	MinusDisabledMessage:  "heart",
+}
+
+// replyModeFor returns the reply mode of a channel: the one stored for the channel,
+// the one configured for the channel, or the workspace-wide one
+func (b *Bot) replyModeFor(channel string) ReplyMode {
+	if mode := b.channelSettings(channel).ReplyMode; mode != "" {
+		return ReplyMode(mode)
+	}
+	if mode, ok := b.channelReplyModes[channel]; ok {
+		return mode
+	}
+	return b.replyMode
+}
+
+// respond responds to a point change message according to the reply mode of its channel.
+// recipient is the user who received the points, who gets the reply as a direct message in
+// the silent mode; it is empty when nobody should be notified.
+func (b *Bot) respond(ev *slackevents.MessageEvent, messageType MessageType, recipient string, r reply) {
+	switch b.replyModeFor(ev.Channel) {
+	case ReplyInThread:
+		threadTS := ev.ThreadTimeStamp
+		if threadTS == "" {
+			threadTS = ev.TimeStamp
+		}
+		b.postReply(ev.Channel, threadTS, r)
+	case ReplyWithReaction:
+		if err := b.api.AddReaction(reactionNames[messageType], slack.NewRefToMessage(ev.Channel, ev.TimeStamp)); err != nil {
+			b.logger.Error("Error adding reaction", "error", err)
+		}
+	case ReplySilently:
+		if recipient != "" {
+			b.postReply(recipient, "", r)
+		}
+	default:
+		b.postReply(ev.Channel, ev.ThreadTimeStamp, r)
+	}
+}
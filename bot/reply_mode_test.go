@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReplyMode(t *testing.T) {
+	for _, s := range []string{"channel", "thread", "reaction", "silent"} {
+		if mode, err := ParseReplyMode(s); err != nil || string(mode) != s {
+			t.Errorf("ParseReplyMode(%q) = %q, %v", s, mode, err)
+		}
+	}
+	if _, err := ParseReplyMode("loud"); err == nil {
+		t.Error("ParseReplyMode(\"loud\") should fail")
+	}
+}
+
+func TestSimulatorReplyModes(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithChannelReplyModes(map[string]ReplyMode{
+		"threads":   ReplyInThread,
+		"reactions": ReplyWithReaction,
+		"quiet":     ReplySilently,
+	})(sim.bot)
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"U1 #general: <@U2>++", "#general: "},
+		{"U1 #threads: <@U2>++", "#threads [thread 3.000000]: "},
+		{"U1 #reactions: <@U2>++", "#reactions [reaction 5.000000]: :heavy_plus_sign:"},
+		{"U1 #reactions: <@U1>++", "#reactions [reaction 6.000000]: :no_entry_sign:"},
+		{"U1 #quiet: <@U2>--", "@U2: "},
+		{"U1 #quiet: :sake:++", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			out.Reset()
+			if err := sim.HandleLine(tt.line); err != nil {
+				t.Fatalf("HandleLine() error = %v", err)
+			}
+			got := out.String()
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("output = %q, want none", got)
+				}
+				return
+			}
+			if !strings.HasPrefix(got, tt.want) || strings.Count(got, "\n") != 1 {
+				t.Errorf("output = %q, want a single line starting with %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack"
+)
+
+func TestPointsReply(t *testing.T) {
+	data := newMessageData("U123456", true, 3).withGiver("U654321")
+	data.Reason = "for the review"
+
+	r := pointsReply("en", PlusPointsMessage, data)
+	if !strings.Contains(r.text, "3 points") {
+		t.Errorf("pointsReply() text = %q", r.text)
+	}
+	if len(r.blocks) != 2 {
+		t.Fatalf("pointsReply() has %d blocks, want a section and a context", len(r.blocks))
+	}
+	section, ok := r.blocks[0].(*slack.SectionBlock)
+	if !ok || section.Text.Text != r.text {
+		t.Errorf("pointsReply() section = %+v, want the text", r.blocks[0])
+	}
+	context, ok := r.blocks[1].(*slack.ContextBlock)
+	if !ok {
+		t.Fatalf("pointsReply() second block = %T, want a context block", r.blocks[1])
+	}
+	text := context.ContextElements.Elements[0].(*slack.TextBlockObject).Text
+	if text != "Given by <@U654321>: for the review" {
+		t.Errorf("pointsReply() context = %q", text)
+	}
+
+	if r := pointsReply("en", EqualsMessage, data); len(r.blocks) != 1 {
+		t.Errorf("pointsReply() for a check has %d blocks, want only the section", len(r.blocks))
+	}
+}
+
+func TestLeaderboardReply(t *testing.T) {
+	records := []repository.UserPoints{
+		{UserID: "U1", Points: 5, IsUser: true},
+		{UserID: "B1", Points: 5},
+		{UserID: "U0002", Points: 5},
+		{UserID: "sake", Points: 1},
+	}
+
+	r := leaderboardReply("ja", records)
+	want := "*ランキング*\n" +
+		"1. <@U1>: 5ポイント\n" +
+		"1. :B1:: 5ポイント\n" +
+		"1. <@U0002>: 5ポイント\n" +
+		"4. :sake:: 1ポイント"
+	if r.text != want {
+		t.Errorf("leaderboardReply() text = %q, want %q", r.text, want)
+	}
+
+	if len(r.blocks) != 2 {
+		t.Fatalf("leaderboardReply() has %d blocks, want a header and a table", len(r.blocks))
+	}
+	table, ok := r.blocks[1].(*slack.TableBlock)
+	if !ok {
+		t.Fatalf("leaderboardReply() second block = %T, want a table", r.blocks[1])
+	}
+	if len(table.Rows) != len(records)+1 {
+		t.Errorf("leaderboardReply() table has %d rows, want a header and %d rows", len(table.Rows), len(records))
+	}
+	if rank := table.Rows[4][0].(*slack.TableRawTextCell).Text; rank != "4" {
+		t.Errorf("leaderboardReply() last rank = %q, want 4", rank)
+	}
+}
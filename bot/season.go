@@ -0,0 +1,224 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"plusplusbot/infra/repository"
+	"plusplusbot/schedule"
+)
+
+// Seasons rolls seasons over on a schedule: the season in progress ends, its standings are archived,
+// and a new season starts
+type Seasons struct {
+	// Schedule is a cron-like schedule, like "0 0 1 1,4,7,10 *" for every quarter; empty disables it
+	Schedule string
+	// Channel is the channel the final standings and new seasons are announced in; empty disables announcements
+	Channel string
+}
+
+// seasonName returns the default name of a season starting at start: its start date
+func (b *Bot) seasonName(start time.Time) string {
+	return start.In(b.timezone()).Format(time.DateOnly)
+}
+
+// startSeasons rolls seasons over on their schedule, in the background
+func (b *Bot) startSeasons() {
+	if b.seasons.Schedule == "" {
+		return
+	}
+	s, err := schedule.Parse(b.seasons.Schedule)
+	if err != nil {
+		b.logger.Error("Invalid season schedule", "error", err)
+		return
+	}
+	go b.runSeasons(s)
+}
+
+// runSeasons rolls the seasons over each time the schedule fires
+func (b *Bot) runSeasons(s *schedule.Schedule) {
+	for {
+		next := s.Next(time.Now().In(b.timezone()))
+		if next.IsZero() {
+			b.logger.Warn("Season schedule never fires", "schedule", b.seasons.Schedule)
+			return
+		}
+		time.Sleep(time.Until(next))
+		b.rollOverSeason(next)
+	}
+}
+
+// rollOverSeason ends the season in progress at t, if any, and starts the next one at t,
+// announcing both in the season channel
+func (b *Bot) rollOverSeason(t time.Time) {
+	ctx := context.Background()
+	locale := b.localeFor(b.seasons.Channel, "")
+	season, standings, err := repository.EndSeason(ctx, b.repo, t)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+	case err != nil:
+		b.logger.Error("Error ending season", "error", err)
+		return
+	default:
+		b.logger.Info("Season ended", "season", season.Name, "standings", len(standings))
+		b.announceSeason(finalStandingsReply(locale, season.Name, standings[:min(len(standings), defaultLeaderboardSize)]))
+	}
+
+	season, err = repository.StartSeason(ctx, b.repo, b.seasonName(t), t)
+	if err != nil {
+		b.logger.Error("Error starting season", "error", err)
+		return
+	}
+	b.logger.Info("Season started", "season", season.Name)
+	messages := (*catalog.Load()).Messages(locale)
+	b.announceSeason(reply{text: renderText(messages.SeasonStarted, MessageData{Season: season.Name})})
+}
+
+// announceSeason posts to the season channel, if there is one
+func (b *Bot) announceSeason(r reply) {
+	if b.seasons.Channel != "" {
+		b.postReply(b.seasons.Channel, "", r)
+	}
+}
+
+// finalStandingsReply creates the ranking of the targets at the end of a season
+func finalStandingsReply(locale, season string, standings []repository.UserPoints) reply {
+	messages := (*catalog.Load()).Messages(locale)
+	data := MessageData{Season: season}
+	return rankingReply(messages, renderText(messages.SeasonFinalTitle, data), renderText(messages.SeasonEmpty, data), standings)
+}
+
+// handleSeasonCommand manages and shows seasons: "season [list|start [name]|end|<name>]".
+// Without an argument, it replies with the standings of the season in progress so far; with the name of
+// a past season, with its final standings. Starting and ending seasons is limited to workspace admins.
+func (b *Bot) handleSeasonCommand(cmd command) {
+	locale := b.localeFor(cmd.channel, cmd.user)
+	messages := (*catalog.Load()).Messages(locale)
+	ctx := context.Background()
+	respond := func(text string, data MessageData) {
+		b.postReply(cmd.channel, cmd.threadTS, reply{text: renderText(text, data)})
+	}
+
+	switch {
+	case len(cmd.args) == 0:
+		season, err := repository.CurrentSeason(ctx, b.repo)
+		if errors.Is(err, repository.ErrNotFound) {
+			respond(messages.SeasonNone, MessageData{})
+			return
+		}
+		if err != nil {
+			b.logger.Error("Error getting season", "error", err)
+			return
+		}
+		records, err := b.repo.ListPointsBetween(ctx, season.Start, time.Now(), defaultLeaderboardSize)
+		if err != nil {
+			b.logger.Error("Error listing points", "error", err)
+			return
+		}
+		data := MessageData{Season: season.Name}
+		b.postReply(cmd.channel, cmd.threadTS,
+			rankingReply(messages, renderText(messages.SeasonTitle, data), renderText(messages.SeasonEmpty, data), records))
+
+	case len(cmd.args) == 1 && cmd.args[0] == "list":
+		seasons, err := b.repo.ListSeasons(ctx)
+		if err != nil {
+			b.logger.Error("Error listing seasons", "error", err)
+			return
+		}
+		b.postReply(cmd.channel, cmd.threadTS, b.seasonsReply(locale, seasons))
+
+	case cmd.args[0] == "start" && len(cmd.args) <= 2:
+		if !b.isWorkspaceAdmin(cmd.user) {
+			respond(messages.NotAdmin, MessageData{})
+			return
+		}
+		now := time.Now()
+		name := b.seasonName(now)
+		if len(cmd.args) == 2 {
+			name = cmd.args[1]
+		}
+		season, err := repository.StartSeason(ctx, b.repo, name, now)
+		switch {
+		case errors.Is(err, repository.ErrSeasonInProgress):
+			current, err := repository.CurrentSeason(ctx, b.repo)
+			if err != nil {
+				b.logger.Error("Error getting season", "error", err)
+				return
+			}
+			respond(messages.SeasonInProgress, MessageData{Season: current.Name})
+		case errors.Is(err, repository.ErrSeasonExists):
+			respond(messages.SeasonExists, MessageData{Season: name})
+		case err != nil:
+			b.logger.Error("Error starting season", "error", err)
+		default:
+			b.logger.Info("Season started", "season", season.Name, "user", cmd.user)
+			respond(messages.SeasonStarted, MessageData{Season: season.Name})
+		}
+
+	case len(cmd.args) == 1 && cmd.args[0] == "end":
+		if !b.isWorkspaceAdmin(cmd.user) {
+			respond(messages.NotAdmin, MessageData{})
+			return
+		}
+		season, standings, err := repository.EndSeason(ctx, b.repo, time.Now())
+		if errors.Is(err, repository.ErrNotFound) {
+			respond(messages.SeasonNone, MessageData{})
+			return
+		}
+		if err != nil {
+			b.logger.Error("Error ending season", "error", err)
+			return
+		}
+		b.logger.Info("Season ended", "season", season.Name, "user", cmd.user)
+		b.postReply(cmd.channel, cmd.threadTS,
+			finalStandingsReply(locale, season.Name, standings[:min(len(standings), defaultLeaderboardSize)]))
+
+	case len(cmd.args) == 1:
+		season, err := repository.FindSeason(ctx, b.repo, cmd.args[0])
+		if errors.Is(err, repository.ErrNotFound) {
+			respond(messages.SeasonNotFound, MessageData{Season: cmd.args[0]})
+			return
+		}
+		if err != nil {
+			b.logger.Error("Error getting season", "error", err)
+			return
+		}
+		if season.End.IsZero() {
+			// The season in progress has no archived standings yet
+			b.handleSeasonCommand(command{name: cmd.name, channel: cmd.channel, user: cmd.user, threadTS: cmd.threadTS})
+			return
+		}
+		standings, err := b.repo.ListSeasonStandings(ctx, season.Name, defaultLeaderboardSize)
+		if err != nil {
+			b.logger.Error("Error listing season standings", "error", err)
+			return
+		}
+		b.postReply(cmd.channel, cmd.threadTS, finalStandingsReply(locale, season.Name, standings))
+
+	default:
+		respond(messages.SeasonUsage, MessageData{})
+	}
+}
+
+// seasonsReply creates the list of seasons, most recent first
+func (b *Bot) seasonsReply(locale string, seasons []repository.Season) reply {
+	messages := (*catalog.Load()).Messages(locale)
+	if len(seasons) == 0 {
+		return reply{text: messages.SeasonsEmpty}
+	}
+
+	text := "*" + messages.SeasonsTitle + "*"
+	for i := len(seasons) - 1; i >= 0; i-- {
+		season := seasons[i]
+		start := season.Start.In(b.timezone())
+		data := MessageData{Season: season.Name, Period: start.Format(time.DateOnly)}
+		line := messages.SeasonsCurrent
+		if !season.End.IsZero() {
+			data.Period = formatPeriod(start, season.End.In(b.timezone()))
+			line = messages.SeasonsLine
+		}
+		text += "\n" + renderText(line, data)
+	}
+	return reply{text: text}
+}
@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeasonCommand(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	sim.SetAdminUsers("UADMIN")
+
+	run := func(line string) string {
+		t.Helper()
+		out.Reset()
+		if err := sim.HandleLine(line); err != nil {
+			t.Fatalf("HandleLine() error = %v", err)
+		}
+		return out.String()
+	}
+
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"no season", "U1 #general: <@UBOT> season", "#general: No season is in progress.\n"},
+		{"no seasons", "U1 #general: <@UBOT> season list", "#general: There are no seasons yet.\n"},
+		{"start by non-admin", "U1 #general: <@UBOT> season start", "#general: Only workspace admins can do that.\n"},
+		{"start", "UADMIN #general: <@UBOT> season start spring",
+			"#general: :checkered_flag: Season spring has started. Everyone is back to zero for this season!\n"},
+		{"start while in progress", "UADMIN #general: <@UBOT> season start summer",
+			"#general: Season spring is still in progress. End it first.\n"},
+		{"empty standings", "U1 #general: <@UBOT> season", "#general: Nobody has received points in season spring.\n"},
+	}
+	for _, tt := range tests {
+		if got := run(tt.line); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	run("U1 #general: <@U2>++")
+	run("U3 #general: <@U2>++")
+	run("U2 #general: :sake:++")
+
+	today := time.Now().UTC().Format(time.DateOnly)
+	tests = []struct {
+		name string
+		line string
+		want string
+	}{
+		{"standings so far", "U1 #general: <@UBOT> season",
+			"#general: *Season spring so far*\n1. <@U2>: 2 points\n2. :sake:: 1 point\n"},
+		{"current season by name", "U1 #general: <@UBOT> season spring",
+			"#general: *Season spring so far*\n1. <@U2>: 2 points\n2. :sake:: 1 point\n"},
+		{"end by non-admin", "U1 #general: <@UBOT> season end", "#general: Only workspace admins can do that.\n"},
+		{"end", "UADMIN #general: <@UBOT> season end",
+			"#general: *Final standings of season spring*\n1. <@U2>: 2 points\n2. :sake:: 1 point\n"},
+		{"end without a season", "UADMIN #general: <@UBOT> season end", "#general: No season is in progress.\n"},
+		{"past season", "U1 #general: <@UBOT> season spring",
+			"#general: *Final standings of season spring*\n1. <@U2>: 2 points\n2. :sake:: 1 point\n"},
+		{"unknown season", "U1 #general: <@UBOT> season winter", "#general: There is no season called winter.\n"},
+		{"start with a used name", "UADMIN #general: <@UBOT> season start spring",
+			"#general: There already is a season called spring.\n"},
+		{"start", "UADMIN #general: <@UBOT> season start summer",
+			"#general: :checkered_flag: Season summer has started. Everyone is back to zero for this season!\n"},
+		{"list", "U1 #general: <@UBOT> season list",
+			"#general: *Seasons*\n• summer: since " + today + " (in progress)\n• spring: " + today + "\n"},
+		{"usage", "U1 #general: <@UBOT> season start summer now", "#general: Usage: `season [list|start [name]|end|<name>]`\n"},
+	}
+	for _, tt := range tests {
+		if got := run(tt.line); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRollOverSeason(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithSeasons(Seasons{Channel: "CSEASON"})(sim.bot)
+
+	first := time.Now().UTC().Add(-time.Minute)
+	sim.bot.rollOverSeason(first)
+	name := first.Format(time.DateOnly)
+	want := "#CSEASON: :checkered_flag: Season " + name + " has started. Everyone is back to zero for this season!\n"
+	if got := out.String(); got != want {
+		t.Errorf("first roll over = %q, want %q", got, want)
+	}
+
+	if err := sim.HandleLine("U1 #general: <@U2>++"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	out.Reset()
+	next := time.Now().UTC().AddDate(0, 0, 1)
+	sim.bot.rollOverSeason(next)
+	want = "#CSEASON: *Final standings of season " + name + "*\n1. <@U2>: 1 point\n" +
+		"#CSEASON: :checkered_flag: Season " + next.Format(time.DateOnly) + " has started. Everyone is back to zero for this season!\n"
+	if got := out.String(); got != want {
+		t.Errorf("second roll over = %q, want %q", got, want)
+	}
+}
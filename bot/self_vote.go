@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// SelfVotePolicy is what happens when someone gives points to themselves or to a target they own
+type SelfVotePolicy string
+
+const (
+	// SelfVoteReject leaves the points unchanged and replies with a self message
+	SelfVoteReject SelfVotePolicy = "reject"
+	// SelfVotePenalty takes a point away from the target and replies with a self message
+	SelfVotePenalty SelfVotePolicy = "penalty"
+)
+
+// userGroupOperationPattern matches user group mentions: <!subteam^S123|@team> ++ (captures group ID and operator)
+var userGroupOperationPattern = regexp.MustCompile(`<!subteam\^([A-Z0-9]+)(?:\|[^>]*)?>[ 　]*(\+\+|-{2})[ 　]*($|\n)`)
+
+// detectUserGroupOperation detects a point change for the members of a user group
+func detectUserGroupOperation(text string) (PointOperation, string) {
+	if matches := userGroupOperationPattern.FindStringSubmatch(text); len(matches) >= 3 {
+		return parseOperator(matches[2]), matches[1]
+	}
+	return NoOperation, ""
+}
+
+// isSelfVote reports whether giver is the target, or one of the users owning a non-user target
+func (b *Bot) isSelfVote(giver, target string, isUser bool) bool {
+	if isUser {
+		return target == giver
+	}
+	return slices.Contains(b.targetOwners[target], giver)
+}
+
+// handleSelfVote responds to someone giving points to themselves or to a target they own.
+// With the penalty policy, the target loses a point, unless negative points are disabled.
+func (b *Bot) handleSelfVote(ev *slackevents.MessageEvent, locale, target string, isUser bool) {
+	b.logger.Info("Self vote detected", "user", ev.User, "target", target)
+
+	points, delta := 0, 0
+	if b.selfVotePolicy == SelfVotePenalty {
+		ctx := repository.WithChannel(context.Background(), ev.Channel)
+		// The self vote is recorded in the history even when negative points are disabled
+		event := pointEvent(ev, target, isUser, -1, false)
+		change, err := b.repo.AddPointsWithEvent(ctx, event, isUser)
+		if errors.Is(err, repository.ErrMinusDisabled) {
+			event.Delta = 0
+			if err = b.repo.AddPointEvent(ctx, event); err == nil {
+				change.Points, err = b.repo.GetPoints(ctx, target)
+			}
+		}
+		if err != nil {
+			b.logger.Error("Error adding points", "error", err)
+			return
+		}
+		points, delta = change.Points, change.Delta
+	}
+
+	b.respond(ev, SelfMessage, "", pointsReply(locale, SelfMessage, b.messageData(ev, target, isUser, points, delta)))
+}
+
+// handleUserGroupPointChange changes the points of every member of a user group except the giver
+func (b *Bot) handleUserGroupPointChange(ev *slackevents.MessageEvent, operation PointOperation, group string) {
+	members, err := b.api.GetUserGroupMembers(group)
+	if err != nil {
+		b.logger.Error("Error getting user group members", "group", group, "error", err)
+		return
+	}
+
+	targets := slices.DeleteFunc(slices.Clone(members), func(member string) bool {
+		return member == ev.User
+	})
+	if len(targets) == 0 {
+		// The giver is the only member
+		b.handleSelfVote(ev, b.localeFor(ev.Channel, ev.User), ev.User, true)
+		return
+	}
+	if len(targets) < len(members) {
+		b.logger.Debug("Excluding the giver from the user group", "group", group, "user", ev.User)
+	}
+
+	var responses []pointChangeResponse
+	for _, member := range targets {
+		response, ok := b.applyPointChange(ev, operation, member, true, 1, "")
+		if !ok {
+			continue
+		}
+		if response.messageType == MinusDisabledMessage {
+			// Negative points are disabled for the whole channel, so the other members would get the same answer
+			b.respond(ev, response.messageType, "", response.reply)
+			return
+		}
+		responses = append(responses, response)
+	}
+	if len(responses) == 0 {
+		return
+	}
+
+	// The changes are answered in one reply, except in the silent reply mode, where each member gets their own
+	if b.replyModeFor(ev.Channel) == ReplySilently {
+		for _, response := range responses {
+			b.respond(ev, response.messageType, response.recipient, response.reply)
+		}
+	} else {
+		replies := make([]reply, 0, len(responses))
+		for _, response := range responses {
+			replies = append(replies, response.reply)
+		}
+		b.respond(ev, responses[0].messageType, "", combineReplies(replies))
+	}
+	for _, response := range responses {
+		response.after()
+	}
+}
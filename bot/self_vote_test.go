@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDetectUserGroupOperation(t *testing.T) {
+	tests := []struct {
+		text      string
+		wantOp    PointOperation
+		wantGroup string
+	}{
+		{"<!subteam^S123|@devs>++", PointUp, "S123"},
+		{"thanks <!subteam^S123> --", PointDown, "S123"},
+		{"<!subteam^S123|@devs>==", NoOperation, ""},
+		{"<@U123>++", NoOperation, ""},
+	}
+	for _, tt := range tests {
+		op, group := detectUserGroupOperation(tt.text)
+		if op != tt.wantOp || group != tt.wantGroup {
+			t.Errorf("detectUserGroupOperation(%q) = %v, %q, want %v, %q", tt.text, op, group, tt.wantOp, tt.wantGroup)
+		}
+	}
+}
+
+func TestSimulatorSelfVote(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     SelfVotePolicy
+		line       string
+		target     string
+		wantPoints int
+	}{
+		{"user rejected", SelfVoteReject, "U1 #general: <@U1>++", "U1", 0},
+		{"owned emoji rejected", SelfVoteReject, "U1 #general: :u1-parrot:++", "u1-parrot", 0},
+		{"user penalized", SelfVotePenalty, "U1 #general: <@U1>++", "U1", -1},
+		{"owned emoji penalized", SelfVotePenalty, "U1 #general: :u1-parrot:++", "u1-parrot", -1},
+		{"emoji owned by someone else", SelfVotePenalty, "U2 #general: :u1-parrot:++", "u1-parrot", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim, _, cleanup := setupTestSimulator(t)
+			defer cleanup()
+			WithSelfVotePolicy(tt.policy)(sim.bot)
+			WithTargetOwners(map[string][]string{"u1-parrot": {"U1"}})(sim.bot)
+
+			if err := sim.HandleLine(tt.line); err != nil {
+				t.Fatalf("HandleLine() error = %v", err)
+			}
+			points, err := sim.bot.repo.GetPoints(context.Background(), tt.target)
+			if err != nil {
+				t.Fatalf("GetPoints() error = %v", err)
+			}
+			if points != tt.wantPoints {
+				t.Errorf("points of %s = %d, want %d", tt.target, points, tt.wantPoints)
+			}
+		})
+	}
+}
+
+func TestSimulatorUserGroup(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	sim.SetUserGroup("S1", "U1", "U2", "U3")
+	sim.SetUserGroup("SSOLO", "U1")
+
+	script := "U1 #general: great launch <!subteam^S1|@launch>++\nU1 #general: <!subteam^SSOLO>++\n"
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for target, want := range map[string]int{"U1": 0, "U2": 1, "U3": 1} {
+		points, err := sim.bot.repo.GetPoints(context.Background(), target)
+		if err != nil {
+			t.Fatalf("GetPoints() error = %v", err)
+		}
+		if points != want {
+			t.Errorf("points of %s = %d, want %d", target, points, want)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("output has %d lines, want a line per member and a self message: %q", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], "<@U2>") || !strings.Contains(lines[1], "<@U3>") {
+		t.Errorf("member replies = %q", lines[:2])
+	}
+	// The members are answered in one reply
+	if !strings.HasPrefix(lines[0], "#general") || strings.HasPrefix(lines[1], "#general") {
+		t.Errorf("member replies = %q, want one reply", lines[:2])
+	}
+}
@@ -0,0 +1,230 @@
+package bot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// simulatorLinePattern matches a simulator input line: "U123 #general: <@U456>++"
+var simulatorLinePattern = regexp.MustCompile(`^(\S+)[ \t]+#([^\s:]+):[ \t]?(.*)$`)
+
+// SimulatorBotUserID is the user ID of the bot in the simulator; lines mentioning it, like
+// "U1 #general: <@UBOT> leaderboard", are also handled as commands
+const SimulatorBotUserID = "UBOT"
+
+// simulatorUserPattern matches user IDs in the simulator, like "U1"
+var simulatorUserPattern = regexp.MustCompile(`^[UW][A-Z0-9]+$`)
+
+// simulatedAPI implements SlackAPI by writing replies to an io.Writer instead of calling Slack
+type simulatedAPI struct {
+	out     io.Writer
+	bots    map[string]bool
+	admins  map[string]bool
+	locales map[string]string
+	groups  map[string][]string
+	ts      int
+}
+
+// nextTimestamp returns a new unique message timestamp
+func (a *simulatedAPI) nextTimestamp() string {
+	a.ts++
+	return fmt.Sprintf("%d.000000", a.ts)
+}
+
+func (a *simulatedAPI) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix := "#" + channelID
+	if simulatorUserPattern.MatchString(channelID) {
+		// Messages posted to a user ID are direct messages
+		prefix = "@" + channelID
+	}
+	if ts := values.Get("thread_ts"); ts != "" {
+		prefix += " [thread " + ts + "]"
+	}
+	if _, err := fmt.Fprintf(a.out, "%s: %s\n", prefix, values.Get("text")); err != nil {
+		return "", "", err
+	}
+	return channelID, a.nextTimestamp(), nil
+}
+
+func (a *simulatedAPI) AddReaction(name string, item slack.ItemRef) error {
+	_, err := fmt.Fprintf(a.out, "#%s [reaction %s]: :%s:\n", item.Channel, item.Timestamp, name)
+	return err
+}
+
+func (a *simulatedAPI) GetPermalink(params *slack.PermalinkParameters) (string, error) {
+	return fmt.Sprintf("https://simulator.slack.com/archives/%s/p%s", params.Channel, strings.ReplaceAll(params.Ts, ".", "")), nil
+}
+
+func (a *simulatedAPI) GetUserInfo(user string) (*slack.User, error) {
+	return &slack.User{ID: user, Name: user, IsBot: a.bots[user], IsAdmin: a.admins[user], Locale: a.locales[user]}, nil
+}
+
+func (a *simulatedAPI) GetUserGroupMembers(userGroup string, options ...slack.GetUserGroupMembersOption) ([]string, error) {
+	members, ok := a.groups[userGroup]
+	if !ok {
+		return nil, fmt.Errorf("no_such_subteam: %s", userGroup)
+	}
+	return members, nil
+}
+
+func (a *simulatedAPI) PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error) {
+	var texts []string
+	for _, block := range view.Blocks.BlockSet {
+		switch block := block.(type) {
+		case *slack.HeaderBlock:
+			texts = append(texts, block.Text.Text)
+		case *slack.SectionBlock:
+			texts = append(texts, block.Text.Text)
+		}
+	}
+	if _, err := fmt.Fprintf(a.out, "@%s [home]: %s\n", userID, strings.Join(texts, "\n")); err != nil {
+		return nil, err
+	}
+	return &slack.ViewResponse{}, nil
+}
+
+func (a *simulatedAPI) OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	var labels []string
+	for _, block := range view.Blocks.BlockSet {
+		if input, ok := block.(*slack.InputBlock); ok {
+			labels = append(labels, input.Label.Text)
+		}
+	}
+	if _, err := fmt.Fprintf(a.out, "[modal %s]: %s (%s)\n", view.CallbackID, view.Title.Text, strings.Join(labels, ", ")); err != nil {
+		return nil, err
+	}
+	return &slack.ViewResponse{}, nil
+}
+
+// Simulator feeds chat lines through the bot's message handlers without connecting to Slack.
+// Each input line has the form "<user> #<channel>: <text>", and replies are written to the
+// output instead of being posted.
+type Simulator struct {
+	bot *Bot
+	api *simulatedAPI
+}
+
+// NewSimulator creates a new Simulator that writes the bot's replies to out.
+// The given seed makes the randomly chosen reply texts reproducible.
+func NewSimulator(repo repository.UserPointsRepository, out io.Writer, seed int64, logger *slog.Logger, opts ...Option) *Simulator {
+	seedMessages(seed)
+
+	api := &simulatedAPI{
+		out:     out,
+		bots:    map[string]bool{},
+		admins:  map[string]bool{},
+		locales: map[string]string{},
+		groups:  map[string][]string{},
+	}
+
+	b := &Bot{
+		api:        api,
+		logger:     logger,
+		repo:       repo,
+		locale:     DefaultLocale,
+		replyMode:  ReplyInChannel,
+		allowMinus: true,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	// Notifications are sent when the input ends rather than after a delay, so that the output is reproducible
+	b.notifications = newNotifier(b.notificationDelay, false, b.sendNotifications)
+
+	return &Simulator{
+		bot: b,
+		api: api,
+	}
+}
+
+// SetBotUsers marks the given user IDs as bot users
+func (s *Simulator) SetBotUsers(userIDs ...string) {
+	for _, id := range userIDs {
+		s.api.bots[id] = true
+	}
+}
+
+// SetAdminUsers marks the given user IDs as workspace admins
+func (s *Simulator) SetAdminUsers(userIDs ...string) {
+	for _, id := range userIDs {
+		s.api.admins[id] = true
+	}
+}
+
+// SetUserLocale sets the Slack locale reported for a user, such as "ja-JP"
+func (s *Simulator) SetUserLocale(userID, locale string) {
+	s.api.locales[userID] = locale
+}
+
+// SetUserGroup sets the members of a user group, mentioned as "<!subteam^S123>"
+func (s *Simulator) SetUserGroup(groupID string, members ...string) {
+	s.api.groups[groupID] = members
+}
+
+// HandleLine processes a single input line. Blank lines and lines starting with "#" are ignored.
+func (s *Simulator) HandleLine(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	matches := simulatorLinePattern.FindStringSubmatch(line)
+	if matches == nil {
+		return fmt.Errorf("invalid line %q: expected \"<user> #<channel>: <text>\"", line)
+	}
+
+	ts := s.api.nextTimestamp()
+	s.bot.handleMessageEvent(&slackevents.MessageEvent{
+		Type:      "message",
+		User:      matches[1],
+		Channel:   matches[2],
+		Text:      matches[3],
+		TimeStamp: ts,
+	})
+
+	// Slack sends an app_mention event in addition to the message event when the bot is mentioned
+	if strings.Contains(matches[3], "<@"+SimulatorBotUserID+">") {
+		s.bot.handleAppMention(&slackevents.AppMentionEvent{
+			Type:      "app_mention",
+			User:      matches[1],
+			Channel:   matches[2],
+			Text:      matches[3],
+			TimeStamp: ts,
+		})
+	}
+	s.bot.homeRefreshes.Wait()
+	return nil
+}
+
+// Flush sends the direct message notifications collected so far
+func (s *Simulator) Flush() {
+	s.bot.notifications.flushAll()
+}
+
+// Run processes every line read from r, and then sends the collected notifications
+func (s *Simulator) Run(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := s.HandleLine(scanner.Text()); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+	s.Flush()
+	return scanner.Err()
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"log/slog"
+	"plusplusbot/infra/repository"
+)
+
+func setupTestSimulator(t *testing.T) (*Simulator, *bytes.Buffer, func()) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	repo, err := repository.NewSQLiteRepository(":memory:", logger)
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	sim := NewSimulator(repository.NewPolicyRepository(repo, repository.DefaultPolicy), out, 1, logger)
+
+	cleanup := func() {
+		if err := repo.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}
+
+	return sim, out, cleanup
+}
+
+func TestSimulatorRun(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	script := strings.Join([]string{
+		"# comments and blank lines are ignored",
+		"",
+		"U1 #general: <@U2>++",
+		"U3 #general: thanks <@U2> ++",
+		"U1 #random: :sake: --",
+		"U1 #random: hello world",
+		"U4 #general: <@U2>==",
+	}, "\n")
+
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	wants := []struct {
+		prefix   string
+		contains string
+	}{
+		{"#general: ", "<@U2>"},
+		{"#general: ", "2 points"},
+		{"#random: ", "-1 point."},
+		{"#general: ", "<@U2> is currently at 2 points."},
+	}
+	if len(lines) != len(wants) {
+		t.Fatalf("Run() output has %d lines, want %d: %q", len(lines), len(wants), out.String())
+	}
+	for i, want := range wants {
+		if !strings.HasPrefix(lines[i], want.prefix) || !strings.Contains(lines[i], want.contains) {
+			t.Errorf("line %d = %q, want prefix %q containing %q", i, lines[i], want.prefix, want.contains)
+		}
+	}
+}
+
+func TestSimulatorIsReproducible(t *testing.T) {
+	script := "U1 #general: <@U2>++\nU1 #general: <@U2>++\nU1 #general: <@U2>--\n"
+
+	var outputs []string
+	for i := 0; i < 2; i++ {
+		sim, out, cleanup := setupTestSimulator(t)
+		if err := sim.Run(strings.NewReader(script)); err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		outputs = append(outputs, out.String())
+		cleanup()
+	}
+
+	if outputs[0] != outputs[1] {
+		t.Errorf("Run() with the same seed produced different output:\n%s\n%s", outputs[0], outputs[1])
+	}
+}
+
+func TestSimulatorSelfAndBotTargets(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	sim.SetBotUsers("B1")
+
+	if err := sim.HandleLine("U1 #general: <@U1>++"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+	if err := sim.HandleLine("U1 #general: <@B1>++"); err != nil {
+		t.Fatalf("HandleLine() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("output has %d lines, want 2: %q", len(lines), out.String())
+	}
+	points, err := sim.bot.repo.GetPoints(context.Background(), "U1")
+	if err != nil {
+		t.Fatalf("GetPoints() error = %v", err)
+	}
+	if points != 0 {
+		t.Errorf("GetPoints() = %v, want 0 for a self point", points)
+	}
+	if !strings.Contains(lines[1], "<@B1>") || !strings.Contains(lines[1], "1 point!") {
+		t.Errorf("bot target reply = %q, want points for <@B1>", lines[1])
+	}
+}
+
+func TestSimulatorHandleLineInvalid(t *testing.T) {
+	sim, _, cleanup := setupTestSimulator(t)
+	defer cleanup()
+
+	tests := []string{
+		"<@U2>++",
+		"U1 general: <@U2>++",
+		"U1 #general <@U2>++",
+	}
+	for _, line := range tests {
+		if err := sim.HandleLine(line); err == nil {
+			t.Errorf("HandleLine(%q) error = nil, want error", line)
+		}
+	}
+}
+
+func TestSimulatorLocales(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	repo, err := repository.NewSQLiteRepository(":memory:", logger)
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+	defer repo.Close()
+
+	out := &bytes.Buffer{}
+	sim := NewSimulator(repo, out, 1, logger,
+		WithLocale("ja"),
+		WithChannelLocales(map[string]string{"global": "en"}),
+		WithUserLocale(true),
+	)
+	sim.SetUserLocale("U3", "en-US")
+
+	script := "U1 #general: <@U2>==\nU1 #global: <@U2>==\nU3 #general: <@U2>==\n"
+	if err := sim.Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := "#general: <@U2> は現在 0ポイント です。\n" +
+		"#global: <@U2> is currently at 0 points.\n" +
+		"#general: <@U2> is currently at 0 points.\n"
+	if out.String() != want {
+		t.Errorf("Run() output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"plusplusbot/infra/repository"
+	"plusplusbot/schedule"
+)
+
+// StreakNudges reminds users on a schedule that their giving streak breaks unless they give points today
+type StreakNudges struct {
+	// Schedule is a cron-like schedule, like "0 16 * * *" for every day at 16:00; empty disables nudges
+	Schedule string
+	// MinStreak is the shortest streak users are reminded of
+	MinStreak int
+}
+
+// updateGivingStreak counts the day of now in the giving streak of a user
+func (b *Bot) updateGivingStreak(ctx context.Context, user string, now time.Time) {
+	streak, err := b.repo.GetGivingStreak(ctx, user)
+	if err != nil {
+		b.logger.Error("Error getting giving streak", "error", err)
+		return
+	}
+
+	today := PeriodDay.start(now.In(b.timezone()))
+	switch streak.LastDay {
+	case today.Format(time.DateOnly):
+		return
+	case today.AddDate(0, 0, -1).Format(time.DateOnly):
+		streak.Current++
+	default:
+		streak.Current = 1
+	}
+	streak.LastDay = today.Format(time.DateOnly)
+	streak.Longest = max(streak.Longest, streak.Current)
+	if err := b.repo.PutGivingStreak(ctx, *streak); err != nil {
+		b.logger.Error("Error saving giving streak", "error", err)
+	}
+}
+
+// currentStreak returns the number of days in a giving streak that is still going at now: one that
+// includes today, or yesterday when points can still be given today to keep it going
+func (b *Bot) currentStreak(streak repository.GivingStreak, now time.Time) int {
+	today := PeriodDay.start(now.In(b.timezone()))
+	switch streak.LastDay {
+	case today.Format(time.DateOnly), today.AddDate(0, 0, -1).Format(time.DateOnly):
+		return streak.Current
+	default:
+		return 0
+	}
+}
+
+// startStreakNudges sends the streak nudges on their schedule, in the background
+func (b *Bot) startStreakNudges() {
+	if b.streakNudges.Schedule == "" {
+		return
+	}
+	s, err := schedule.Parse(b.streakNudges.Schedule)
+	if err != nil {
+		b.logger.Error("Invalid streak nudge schedule", "error", err)
+		return
+	}
+	go b.runStreakNudges(s)
+}
+
+// runStreakNudges sends the streak nudges each time the schedule fires
+func (b *Bot) runStreakNudges(s *schedule.Schedule) {
+	for {
+		next := s.Next(time.Now().In(b.timezone()))
+		if next.IsZero() {
+			b.logger.Warn("Streak nudge schedule never fires", "schedule", b.streakNudges.Schedule)
+			return
+		}
+		time.Sleep(time.Until(next))
+		b.nudgeStreaks(context.Background(), next)
+	}
+}
+
+// nudgeStreaks sends a direct message to every user whose streak of at least the minimum length
+// breaks unless they give points on the day of now
+func (b *Bot) nudgeStreaks(ctx context.Context, now time.Time) {
+	streaks, err := b.repo.ListGivingStreaks(ctx)
+	if err != nil {
+		b.logger.Error("Error listing giving streaks", "error", err)
+		return
+	}
+
+	yesterday := PeriodDay.start(now.In(b.timezone())).AddDate(0, 0, -1).Format(time.DateOnly)
+	for _, streak := range streaks {
+		if streak.LastDay != yesterday || streak.Current < max(b.streakNudges.MinStreak, 1) {
+			continue
+		}
+		b.logger.Info("Nudging giving streak", "user", streak.UserID, "streak", streak.Current)
+		data := newMessageData(streak.UserID, true, 0)
+		data.Streak, data.LongestStreak = streak.Current, streak.Longest
+		messages := (*catalog.Load()).Messages(b.localeFor("", streak.UserID))
+		b.postReply(streak.UserID, "", reply{text: renderText(messages.StreakNudge, data)})
+	}
+}
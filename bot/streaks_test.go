@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"plusplusbot/infra/repository"
+)
+
+func TestUpdateGivingStreak(t *testing.T) {
+	sim, _, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	WithTimezone(tokyo)(sim.bot)
+
+	ctx := context.Background()
+	day := time.Date(2025, 5, 16, 12, 0, 0, 0, tokyo)
+	steps := []struct {
+		name string
+		now  time.Time
+		want repository.GivingStreak
+	}{
+		{"first day", day, repository.GivingStreak{UserID: "U1", Current: 1, Longest: 1, LastDay: "2025-05-16"}},
+		{"same day", day.Add(time.Hour), repository.GivingStreak{UserID: "U1", Current: 1, Longest: 1, LastDay: "2025-05-16"}},
+		// 23:30 UTC is already the next day in Tokyo
+		{"next day", time.Date(2025, 5, 16, 23, 30, 0, 0, time.UTC), repository.GivingStreak{UserID: "U1", Current: 2, Longest: 2, LastDay: "2025-05-17"}},
+		{"day after", day.AddDate(0, 0, 2), repository.GivingStreak{UserID: "U1", Current: 3, Longest: 3, LastDay: "2025-05-18"}},
+		{"broken", day.AddDate(0, 0, 4), repository.GivingStreak{UserID: "U1", Current: 1, Longest: 3, LastDay: "2025-05-20"}},
+	}
+	for _, step := range steps {
+		sim.bot.updateGivingStreak(ctx, "U1", step.now)
+		got, err := sim.bot.repo.GetGivingStreak(ctx, "U1")
+		if err != nil {
+			t.Fatalf("GetGivingStreak() error = %v", err)
+		}
+		if *got != step.want {
+			t.Errorf("%s: streak = %+v, want %+v", step.name, *got, step.want)
+		}
+	}
+
+	streak := repository.GivingStreak{UserID: "U1", Current: 3, Longest: 5, LastDay: "2025-05-16"}
+	currentTests := []struct {
+		now  time.Time
+		want int
+	}{
+		{day, 3},
+		{day.AddDate(0, 0, 1), 3},
+		{day.AddDate(0, 0, 2), 0},
+	}
+	for _, tt := range currentTests {
+		if got := sim.bot.currentStreak(streak, tt.now); got != tt.want {
+			t.Errorf("currentStreak(%v) = %d, want %d", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestNudgeStreaks(t *testing.T) {
+	sim, out, cleanup := setupTestSimulator(t)
+	defer cleanup()
+	WithStreakNudges(StreakNudges{MinStreak: 2})(sim.bot)
+
+	ctx := context.Background()
+	now := time.Date(2025, 5, 16, 16, 0, 0, 0, time.UTC)
+	for _, streak := range []repository.GivingStreak{
+		{UserID: "U1", Current: 3, Longest: 3, LastDay: "2025-05-15"},
+		// Too short
+		{UserID: "U2", Current: 1, Longest: 4, LastDay: "2025-05-15"},
+		// Already gave points today
+		{UserID: "U3", Current: 5, Longest: 5, LastDay: "2025-05-16"},
+		// Already broken
+		{UserID: "U4", Current: 5, Longest: 5, LastDay: "2025-05-14"},
+	} {
+		if err := sim.bot.repo.PutGivingStreak(ctx, streak); err != nil {
+			t.Fatalf("PutGivingStreak() error = %v", err)
+		}
+	}
+
+	sim.bot.nudgeStreaks(ctx, now)
+	want := "@U1: :fire: You have given points 3 days in a row. Give someone a point today to keep your streak going!\n"
+	if got := out.String(); got != want {
+		t.Errorf("nudges = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"plusplusbot/bot"
+	"plusplusbot/infra/config"
+)
+
+// runConfig runs a configuration subcommand
+func runConfig(args []string) int {
+	if len(args) != 1 || args[0] != "check" {
+		fmt.Fprintln(os.Stderr, "Usage: plusplusbot [-config file] config check")
+		return 2
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	errs := []error{cfg.Validate(), cfg.ValidateSlack()}
+	if cfg.MessagesPath != "" {
+		if _, err := bot.LoadCatalog(cfg.MessagesPath); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		fmt.Fprintln(os.Stderr, "Configuration is invalid:")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintln(os.Stderr, "  - "+line)
+		}
+		return 1
+	}
+
+	fmt.Println("Configuration is valid")
+	return 0
+}
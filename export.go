@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"plusplusbot/infra/repository"
+	"plusplusbot/transfer"
+)
+
+// runExport writes all points in the configured repository to stdout or a file
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: plusplusbot export [options]")
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "", "output format (csv or jsonl); defaults to the output file extension, or jsonl")
+	output := fs.String("o", "", "output file; defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, logger := loadConfig(os.Stderr)
+	if cfg == nil {
+		return 1
+	}
+	if err := cfg.Validate(); err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		return 1
+	}
+
+	f := transfer.FormatFromPath(*output)
+	if *format != "" {
+		var err error
+		if f, err = transfer.ParseFormat(*format); err != nil {
+			logger.Error("Invalid format", "error", err)
+			return 2
+		}
+	}
+
+	repo, err := repository.NewRepository(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to create repository", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Error("Failed to close repository", "error", err)
+		}
+	}()
+
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if *output != "" {
+		file, err = os.Create(*output)
+		if err != nil {
+			logger.Error("Failed to create output file", "error", err)
+			return 1
+		}
+		w = file
+	}
+
+	count, err := transfer.Export(context.Background(), repo, w, f)
+	if file != nil {
+		// Closing flushes the file, so a failure means the export is incomplete
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		logger.Error("Export failed", "error", err, "exported", count)
+		return 1
+	}
+	logger.Info("Export completed", "records", count)
+	return 0
+}
+
+// runImport reads points from stdin or a file into the configured repository
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: plusplusbot import [options] [file]")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "Existing targets are overwritten, so importing the same file twice is safe.")
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+	}
+	format := fs.String("format", "", "input format (csv or jsonl); defaults to the input file extension, or jsonl")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, logger := loadConfig(os.Stderr)
+	if cfg == nil {
+		return 1
+	}
+	if err := cfg.Validate(); err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		return 1
+	}
+
+	f := transfer.FormatFromPath(fs.Arg(0))
+	if *format != "" {
+		var err error
+		if f, err = transfer.ParseFormat(*format); err != nil {
+			logger.Error("Invalid format", "error", err)
+			return 2
+		}
+	}
+
+	repo, err := repository.NewRepository(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to create repository", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Error("Failed to close repository", "error", err)
+		}
+	}()
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			logger.Error("Failed to open input file", "error", err)
+			return 1
+		}
+		defer file.Close()
+		r = file
+	}
+
+	count, err := transfer.Import(context.Background(), repo, r, f)
+	if err != nil {
+		logger.Error("Import failed", "error", err, "imported", count)
+		return 1
+	}
+	logger.Info("Import completed", "records", count)
+	return 0
+}
@@ -10,6 +10,7 @@ require (
 	github.com/guregu/dynamo/v2 v2.6.0
 	github.com/ncruces/go-sqlite3 v0.32.0
 	github.com/slack-go/slack v0.29.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"plusplusbot/admin"
+	"plusplusbot/infra/repository"
+	"plusplusbot/transfer"
+
+	"github.com/slack-go/slack"
+)
+
+// runImportKarma merges scores exported from other karma bots into the configured repository
+func runImportKarma(args []string) int {
+	fs := flag.NewFlagSet("import-karma", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: plusplusbot import-karma [options] <file>")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "Adds scores from another karma bot to the configured repository. User names are")
+		fmt.Fprintln(fs.Output(), "mapped to Slack user IDs with users.list when SLACK_BOT_TOKEN is set.")
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+	}
+	source := fs.String("source", "", "source format: plusplus-dump (pg_dump of pluspl.us), plusplus-json or hubot; defaults to plusplus-dump for .sql files and hubot otherwise")
+	teamID := fs.String("team", "", "only import scores of this pluspl.us team ID")
+	dryRun := fs.Bool("dry-run", false, "print the changes without applying them")
+	actor := fs.String("actor", defaultActor(), "name recorded as the actor in the audit log")
+	reason := fs.String("reason", "", "reason recorded in the audit log and the point history")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, logger := loadConfig(os.Stderr)
+	if cfg == nil {
+		return 1
+	}
+	if err := cfg.Validate(); err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		return 1
+	}
+	ctx := context.Background()
+
+	path := fs.Arg(0)
+	if *source == "" {
+		*source = "hubot"
+		if strings.EqualFold(filepath.Ext(path), ".sql") {
+			*source = "plusplus-dump"
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Error("Failed to open input file", "error", err)
+		return 1
+	}
+	defer file.Close()
+
+	var read func(io.Reader) ([]transfer.KarmaEntry, error)
+	switch *source {
+	case "plusplus-dump":
+		read = func(r io.Reader) ([]transfer.KarmaEntry, error) { return transfer.ReadPlusplusDump(r, *teamID) }
+	case "plusplus-json":
+		read = func(r io.Reader) ([]transfer.KarmaEntry, error) { return transfer.ReadPlusplusJSON(r, *teamID) }
+	case "hubot":
+		read = transfer.ReadHubotBrain
+	default:
+		logger.Error("Unsupported source", "source", *source)
+		return 2
+	}
+
+	entries, err := read(file)
+	if err != nil {
+		logger.Error("Failed to read scores", "error", err)
+		return 1
+	}
+
+	var dir *transfer.UserDirectory
+	if token := cfg.SlackBotToken; token != "" {
+		users, err := slack.New(token).GetUsersContext(ctx)
+		if err != nil {
+			logger.Error("Failed to list Slack users", "error", err)
+			return 1
+		}
+		dir = transfer.NewUserDirectory(users)
+	} else {
+		logger.Warn("SLACK_BOT_TOKEN is not set; only entries that are already user IDs are mapped to users")
+	}
+
+	repo, err := repository.NewRepository(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to create repository", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Error("Failed to close repository", "error", err)
+		}
+	}()
+
+	plan, err := transfer.PlanMerge(ctx, repo, entries, dir)
+	if err != nil {
+		logger.Error("Failed to plan merge", "error", err)
+		return 1
+	}
+	if err := plan.WriteReport(os.Stdout); err != nil {
+		logger.Error("Failed to write report", "error", err)
+		return 1
+	}
+
+	if *dryRun {
+		fmt.Println("Dry run: no changes were made")
+		return 0
+	}
+
+	auditFile, err := os.OpenFile(cfg.AuditLogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		logger.Error("Failed to open audit log", "error", err)
+		return 1
+	}
+	defer auditFile.Close()
+
+	// Every target written is recorded, so that a failed merge can be checked against the audit log
+	a := admin.New(repo, admin.NewAuditLog(auditFile), *actor).WithReason(*reason)
+	if err := a.MergeKarma(ctx, plan); err != nil {
+		logger.Error("Failed to merge scores", "error", err)
+		return 1
+	}
+	return 0
+}
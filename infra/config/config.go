@@ -1,7 +1,20 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"plusplusbot/schedule"
+
+	"gopkg.in/yaml.v3"
 )
 
 // RepositoryType represents the type of repository to use
@@ -15,41 +28,502 @@ const (
 	DynamoDBRepository RepositoryType = "dynamodb"
 )
 
-// Config holds the configuration for the repositories
+// localePattern matches locales like "ja", "en-US" or "pt_BR"
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})?$`)
+
+// replyModes are the valid reply modes
+var replyModes = []string{"channel", "thread", "reaction", "silent"}
+
+// selfVotePolicies are the valid self vote policies
+var selfVotePolicies = []string{"reject", "penalty"}
+
+// digestPeriods are the valid periods of digests and per-period decay
+var digestPeriods = []string{"day", "week", "month", "quarter"}
+
+// Digest is a summary of the points given in a period, posted to a channel on a schedule
+type Digest struct {
+	// Schedule is a cron-like schedule: minute, hour, day of month, month and day of week
+	Schedule string `yaml:"schedule"`
+	// Period is the period summarized: day, week, month or quarter
+	Period string `yaml:"period"`
+	// Channel is the channel ID the digest is posted to
+	Channel string `yaml:"channel"`
+}
+
+// badgeRules are the valid rules of badges
+var badgeRules = []string{"received", "points", "givers", "given", "recipients", "giving_streak"}
+
+// Badge is an achievement awarded to users once they meet its rule
+type Badge struct {
+	// ID identifies the badge in storage
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Emoji       string `yaml:"emoji"`
+	Description string `yaml:"description"`
+	// Rule is what the user must achieve: received, points, givers, given, recipients or giving_streak
+	Rule string `yaml:"rule"`
+	// Threshold is the number of points, users or days the rule requires
+	Threshold int `yaml:"threshold"`
+}
+
+// Config holds the configuration for the bot and the repositories.
+// Each field can be set in the configuration file with the key in its yaml tag,
+// and the environment variable noted in its comment overrides it.
 type Config struct {
-	// RepositoryType is the type of repository to use
-	RepositoryType RepositoryType
+	// SlackBotToken is the Slack bot token (SLACK_BOT_TOKEN)
+	SlackBotToken string `yaml:"slack_bot_token"`
+
+	// SlackAppToken is the Slack app-level token (SLACK_APP_TOKEN)
+	SlackAppToken string `yaml:"slack_app_token"`
+
+	// Debug enables debug logging (DEBUG)
+	Debug bool `yaml:"debug"`
+
+	// RepositoryType is the type of repository to use (REPOSITORY_TYPE)
+	RepositoryType RepositoryType `yaml:"repository_type"`
+
+	// SQLiteDBPath is the path to the SQLite database (DATABASE_URL)
+	SQLiteDBPath string `yaml:"database_url"`
+
+	// DynamoDBTableName is the name of the DynamoDB table (DYNAMO_USER_POINTS_TABLE)
+	DynamoDBTableName string `yaml:"dynamodb_table"`
+
+	// DynamoDBLocal indicates whether to use a local DynamoDB instance (DYNAMO_LOCAL)
+	DynamoDBLocal bool `yaml:"dynamodb_local"`
+
+	// DynamoDBCreateTables lets the bot create missing DynamoDB tables on AWS when it starts (DYNAMO_CREATE_TABLES).
+	// Tables are always created on DynamoDB Local.
+	DynamoDBCreateTables bool `yaml:"dynamodb_create_tables"`
+
+	// AuditLogPath is the path of the file admin operations are recorded to (AUDIT_LOG_PATH)
+	AuditLogPath string `yaml:"audit_log_path"`
+
+	// MessagesPath is a JSON file or a directory of JSON files overriding the reply messages (MESSAGES_PATH)
+	MessagesPath string `yaml:"messages_path"`
+
+	// Locale is the workspace-wide locale of replies, such as "en" or "ja" (LOCALE)
+	Locale string `yaml:"locale"`
+
+	// ChannelLocales overrides the locale of replies per channel ID
+	ChannelLocales map[string]string `yaml:"channel_locales"`
+
+	// UseUserLocale enables replying in the Slack locale of the user who sent the message,
+	// unless a locale is set for the channel (USE_USER_LOCALE)
+	UseUserLocale bool `yaml:"use_user_locale"`
+
+	// MessagesReloadInterval is how often the messages are checked for changes; zero disables polling (MESSAGES_RELOAD_INTERVAL)
+	MessagesReloadInterval time.Duration `yaml:"messages_reload_interval"`
+
+	// RichReplies enables Block Kit replies with a plain text fallback (RICH_REPLIES)
+	RichReplies bool `yaml:"rich_replies"`
+
+	// Milestones are the totals celebrated when a target reaches them, such as 100 (MILESTONES, comma separated)
+	Milestones []int `yaml:"milestones"`
+
+	// ShameMilestones are the negative totals announced when a target sinks to them (SHAME_MILESTONES, comma separated)
+	ShameMilestones []int `yaml:"shame_milestones"`
+
+	// MilestoneChannel is the channel ID milestones are also announced in (MILESTONE_CHANNEL)
+	MilestoneChannel string `yaml:"milestone_channel"`
+
+	// ReplyMode is how the bot responds to point changes: channel, thread, reaction or silent (REPLY_MODE)
+	ReplyMode string `yaml:"reply_mode"`
 
-	// SQLiteDBPath is the path to the SQLite database
-	SQLiteDBPath string
+	// ChannelReplyModes overrides the reply mode per channel ID
+	ChannelReplyModes map[string]string `yaml:"channel_reply_modes"`
 
-	// DynamoDBTableName is the name of the DynamoDB table
-	DynamoDBTableName string
+	// NotificationDelay is how long point changes are collected before notifying their recipient
+	// in one direct message; zero sends a message for every change (NOTIFICATION_DELAY)
+	NotificationDelay time.Duration `yaml:"notification_delay"`
 
-	// DynamoDBLocal indicates whether to use a local DynamoDB instance
-	DynamoDBLocal bool
+	// ChannelAllowlist makes the bot respond only in channels enabled with the channel command (CHANNEL_ALLOWLIST)
+	ChannelAllowlist bool `yaml:"channel_allowlist"`
+
+	// BotManagers are the user IDs allowed to change channel settings besides workspace admins and owners
+	// (BOT_MANAGERS, comma separated)
+	BotManagers []string `yaml:"bot_managers"`
+
+	// DisableMinus disables taking points away with --, unless allowed for a channel (DISABLE_MINUS)
+	DisableMinus bool `yaml:"disable_minus"`
+
+	// FloorAtZero keeps totals from going below zero (FLOOR_AT_ZERO)
+	FloorAtZero bool `yaml:"floor_at_zero"`
+
+	// SelfVote is what happens when someone gives points to themselves or to a target they own:
+	// reject or penalty (SELF_VOTE)
+	SelfVote string `yaml:"self_vote"`
+
+	// TargetOwners are the user IDs owning each emoji target, who cannot give points to it
+	TargetOwners map[string][]string `yaml:"target_owners"`
+
+	// CollusionThreshold is how many times two users must give each other points within CollusionWindow
+	// to be flagged for reciprocal giving; zero, the default, disables the detection (COLLUSION_THRESHOLD)
+	CollusionThreshold int `yaml:"collusion_threshold"`
+
+	// CollusionWindow is the time window reciprocal giving is counted in (COLLUSION_WINDOW)
+	CollusionWindow time.Duration `yaml:"collusion_window"`
+
+	// CollusionDampen stops adding the points two flagged users give each other (COLLUSION_DAMPEN)
+	CollusionDampen bool `yaml:"collusion_dampen"`
+
+	// CollusionReportUsers are the user IDs notified by direct message when a pair of users is flagged
+	// (COLLUSION_REPORT_USERS, comma separated)
+	CollusionReportUsers []string `yaml:"collusion_report_users"`
+
+	// Timezone is the IANA time zone period boundaries are computed in, like "Asia/Tokyo" (TIMEZONE)
+	Timezone string `yaml:"timezone"`
+
+	// Digests are the summaries of the points given in a period posted on a schedule. A single digest can
+	// also be set with DIGEST_CHANNEL, DIGEST_SCHEDULE (default "0 9 * * 1") and DIGEST_PERIOD (default week).
+	Digests []Digest `yaml:"digests"`
+
+	// SeasonSchedule is the cron-like schedule seasons end and the next ones start on, like "0 0 1 1,4,7,10 *"
+	// for quarterly seasons; empty leaves starting and ending seasons to admins (SEASON_SCHEDULE)
+	SeasonSchedule string `yaml:"season_schedule"`
+	// SeasonChannel is the channel ID the final standings and new seasons are announced in (SEASON_CHANNEL)
+	SeasonChannel string `yaml:"season_channel"`
+
+	// DecayHalfLife is how long it takes points to lose half of their weight in rankings, like "720h";
+	// zero disables exponential decay (DECAY_HALF_LIFE)
+	DecayHalfLife time.Duration `yaml:"decay_half_life"`
+	// DecayRate is the share of their weight, between 0 and 1, points lose in rankings at the start of
+	// every DecayPeriod; zero disables per-period decay (DECAY_RATE)
+	DecayRate float64 `yaml:"decay_rate"`
+	// DecayPeriod is the period of per-period decay: day, week, month or quarter (DECAY_PERIOD)
+	DecayPeriod string `yaml:"decay_period"`
+
+	// StreakNudgeSchedule is the cron-like schedule users are reminded on that their giving streak breaks
+	// unless they give points that day, like "0 16 * * *"; empty disables the reminders (STREAK_NUDGE_SCHEDULE)
+	StreakNudgeSchedule string `yaml:"streak_nudge_schedule"`
+	// StreakNudgeMin is the shortest giving streak users are reminded of (STREAK_NUDGE_MIN)
+	StreakNudgeMin int `yaml:"streak_nudge_min"`
+
+	// Badges are the achievements users earn (configuration file only)
+	Badges []Badge `yaml:"badges"`
 }
 
-// NewConfig creates a new Config instance from environment variables
-func NewConfig() *Config {
-	repoType := RepositoryType(os.Getenv("REPOSITORY_TYPE"))
-	if repoType == "" {
-		repoType = SQLiteRepository
+// defaultConfig returns a Config with default values
+func defaultConfig() *Config {
+	return &Config{
+		RepositoryType:    SQLiteRepository,
+		DynamoDBTableName: "user_points",
+		AuditLogPath:      "plusplusbot-audit.log",
+
+		MessagesReloadInterval: 10 * time.Second,
+		Locale:                 "en",
+		ReplyMode:              "channel",
+		NotificationDelay:      time.Minute,
+		SelfVote:               "reject",
+		CollusionWindow:        24 * time.Hour,
+		Timezone:               "UTC",
+		StreakNudgeMin:         2,
 	}
+}
 
-	dbPath := os.Getenv("DATABASE_URL")
+// Load creates a new Config instance from a YAML configuration file, overridden by environment variables.
+// If path is empty, only environment variables are used. Unknown keys in the file are rejected.
+// The returned Config is not validated yet; call Validate before using it.
+func Load(path string) (*Config, error) {
+	cfg := defaultConfig()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
 
-	tableName := os.Getenv("DYNAMO_USER_POINTS_TABLE")
-	if tableName == "" {
-		tableName = "user_points"
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
 	}
+	return cfg, nil
+}
 
-	dynamoLocal := os.Getenv("DYNAMO_LOCAL") != ""
+// applyEnv overrides the configuration with the environment variables that are set
+func (c *Config) applyEnv() error {
+	var errs []error
 
-	return &Config{
-		RepositoryType:    repoType,
-		SQLiteDBPath:      dbPath,
-		DynamoDBTableName: tableName,
-		DynamoDBLocal:     dynamoLocal,
+	setString := func(dst *string, key string) {
+		if v := os.Getenv(key); v != "" {
+			*dst = v
+		}
+	}
+	setBool := func(dst *bool, key string) {
+		if v := os.Getenv(key); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = b
+		}
+	}
+	setDuration := func(dst *time.Duration, key string) {
+		if v := os.Getenv(key); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = d
+		}
 	}
+
+	setInt := func(dst *int, key string) {
+		if v := os.Getenv(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = n
+		}
+	}
+
+	setFloat := func(dst *float64, key string) {
+		if v := os.Getenv(key); v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*dst = f
+		}
+	}
+
+	setInts := func(dst *[]int, key string) {
+		if v := os.Getenv(key); v != "" {
+			var values []int
+			for _, field := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(field))
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", key, err))
+					return
+				}
+				values = append(values, n)
+			}
+			*dst = values
+		}
+	}
+
+	setStrings := func(dst *[]string, key string) {
+		if v := os.Getenv(key); v != "" {
+			var values []string
+			for _, field := range strings.Split(v, ",") {
+				if field = strings.TrimSpace(field); field != "" {
+					values = append(values, field)
+				}
+			}
+			*dst = values
+		}
+	}
+
+	setString(&c.SlackBotToken, "SLACK_BOT_TOKEN")
+	setString(&c.SlackAppToken, "SLACK_APP_TOKEN")
+	setBool(&c.Debug, "DEBUG")
+	if v := os.Getenv("REPOSITORY_TYPE"); v != "" {
+		c.RepositoryType = RepositoryType(v)
+	}
+	setString(&c.SQLiteDBPath, "DATABASE_URL")
+	setString(&c.DynamoDBTableName, "DYNAMO_USER_POINTS_TABLE")
+	setBool(&c.DynamoDBLocal, "DYNAMO_LOCAL")
+	setBool(&c.DynamoDBCreateTables, "DYNAMO_CREATE_TABLES")
+	setString(&c.AuditLogPath, "AUDIT_LOG_PATH")
+	setString(&c.MessagesPath, "MESSAGES_PATH")
+	setDuration(&c.MessagesReloadInterval, "MESSAGES_RELOAD_INTERVAL")
+	setString(&c.Locale, "LOCALE")
+	setBool(&c.UseUserLocale, "USE_USER_LOCALE")
+	setBool(&c.RichReplies, "RICH_REPLIES")
+	setInts(&c.Milestones, "MILESTONES")
+	setInts(&c.ShameMilestones, "SHAME_MILESTONES")
+	setString(&c.MilestoneChannel, "MILESTONE_CHANNEL")
+	setString(&c.ReplyMode, "REPLY_MODE")
+	setDuration(&c.NotificationDelay, "NOTIFICATION_DELAY")
+	setBool(&c.ChannelAllowlist, "CHANNEL_ALLOWLIST")
+	setStrings(&c.BotManagers, "BOT_MANAGERS")
+	setBool(&c.DisableMinus, "DISABLE_MINUS")
+	setBool(&c.FloorAtZero, "FLOOR_AT_ZERO")
+	setString(&c.SelfVote, "SELF_VOTE")
+	setInt(&c.CollusionThreshold, "COLLUSION_THRESHOLD")
+	setDuration(&c.CollusionWindow, "COLLUSION_WINDOW")
+	setBool(&c.CollusionDampen, "COLLUSION_DAMPEN")
+	setStrings(&c.CollusionReportUsers, "COLLUSION_REPORT_USERS")
+	setString(&c.Timezone, "TIMEZONE")
+	if channel := os.Getenv("DIGEST_CHANNEL"); channel != "" {
+		digest := Digest{Schedule: "0 9 * * 1", Period: "week", Channel: channel}
+		setString(&digest.Schedule, "DIGEST_SCHEDULE")
+		setString(&digest.Period, "DIGEST_PERIOD")
+		c.Digests = []Digest{digest}
+	}
+	setString(&c.SeasonSchedule, "SEASON_SCHEDULE")
+	setString(&c.SeasonChannel, "SEASON_CHANNEL")
+	setString(&c.StreakNudgeSchedule, "STREAK_NUDGE_SCHEDULE")
+	setInt(&c.StreakNudgeMin, "STREAK_NUDGE_MIN")
+	setDuration(&c.DecayHalfLife, "DECAY_HALF_LIFE")
+	setFloat(&c.DecayRate, "DECAY_RATE")
+	setString(&c.DecayPeriod, "DECAY_PERIOD")
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the configuration and reports every problem found at once
+func (c *Config) Validate() error {
+	var errs []error
+
+	switch c.RepositoryType {
+	case SQLiteRepository:
+		if c.SQLiteDBPath == "" {
+			errs = append(errs, errors.New("database_url (DATABASE_URL) is required for the sqlite repository"))
+		}
+	case DynamoDBRepository:
+		if c.DynamoDBTableName == "" {
+			errs = append(errs, errors.New("dynamodb_table (DYNAMO_USER_POINTS_TABLE) is required for the dynamodb repository"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("repository_type (REPOSITORY_TYPE) must be %q or %q, got %q", SQLiteRepository, DynamoDBRepository, c.RepositoryType))
+	}
+
+	if c.AuditLogPath == "" {
+		errs = append(errs, errors.New("audit_log_path (AUDIT_LOG_PATH) must not be empty"))
+	}
+
+	if c.Locale != "" && !localePattern.MatchString(c.Locale) {
+		errs = append(errs, fmt.Errorf("locale (LOCALE) must be a locale like \"en\" or \"ja\", got %q", c.Locale))
+	}
+	for channel, locale := range c.ChannelLocales {
+		if !localePattern.MatchString(locale) {
+			errs = append(errs, fmt.Errorf("channel_locales.%s must be a locale like \"en\" or \"ja\", got %q", channel, locale))
+		}
+	}
+
+	if c.MessagesReloadInterval < 0 {
+		errs = append(errs, errors.New("messages_reload_interval (MESSAGES_RELOAD_INTERVAL) must not be negative"))
+	}
+
+	if c.NotificationDelay < 0 {
+		errs = append(errs, errors.New("notification_delay (NOTIFICATION_DELAY) must not be negative"))
+	}
+
+	for _, milestone := range c.Milestones {
+		if milestone <= 0 {
+			errs = append(errs, fmt.Errorf("milestones (MILESTONES) must be positive, got %d", milestone))
+		}
+	}
+	for _, milestone := range c.ShameMilestones {
+		if milestone >= 0 {
+			errs = append(errs, fmt.Errorf("shame_milestones (SHAME_MILESTONES) must be negative, got %d", milestone))
+		}
+	}
+
+	if c.ReplyMode != "" && !slices.Contains(replyModes, c.ReplyMode) {
+		errs = append(errs, fmt.Errorf("reply_mode (REPLY_MODE) must be one of %s, got %q", strings.Join(replyModes, ", "), c.ReplyMode))
+	}
+	for channel, mode := range c.ChannelReplyModes {
+		if !slices.Contains(replyModes, mode) {
+			errs = append(errs, fmt.Errorf("channel_reply_modes.%s must be one of %s, got %q", channel, strings.Join(replyModes, ", "), mode))
+		}
+	}
+
+	if c.CollusionThreshold < 0 {
+		errs = append(errs, errors.New("collusion_threshold (COLLUSION_THRESHOLD) must not be negative"))
+	}
+	if c.CollusionThreshold > 0 && c.CollusionWindow <= 0 {
+		errs = append(errs, errors.New("collusion_window (COLLUSION_WINDOW) must be positive"))
+	}
+
+	if c.SelfVote != "" && !slices.Contains(selfVotePolicies, c.SelfVote) {
+		errs = append(errs, fmt.Errorf("self_vote (SELF_VOTE) must be one of %s, got %q", strings.Join(selfVotePolicies, ", "), c.SelfVote))
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		errs = append(errs, fmt.Errorf("timezone (TIMEZONE) must be a time zone like \"Asia/Tokyo\": %w", err))
+	}
+	for i, digest := range c.Digests {
+		if _, err := schedule.Parse(digest.Schedule); err != nil {
+			errs = append(errs, fmt.Errorf("digests[%d].schedule: %w", i, err))
+		}
+		if !slices.Contains(digestPeriods, digest.Period) {
+			errs = append(errs, fmt.Errorf("digests[%d].period must be one of %s, got %q", i, strings.Join(digestPeriods, ", "), digest.Period))
+		}
+		if digest.Channel == "" {
+			errs = append(errs, fmt.Errorf("digests[%d].channel is required", i))
+		}
+	}
+	if c.SeasonSchedule != "" {
+		if _, err := schedule.Parse(c.SeasonSchedule); err != nil {
+			errs = append(errs, fmt.Errorf("season_schedule (SEASON_SCHEDULE): %w", err))
+		}
+	}
+
+	if c.StreakNudgeSchedule != "" {
+		if _, err := schedule.Parse(c.StreakNudgeSchedule); err != nil {
+			errs = append(errs, fmt.Errorf("streak_nudge_schedule (STREAK_NUDGE_SCHEDULE): %w", err))
+		}
+		if c.StreakNudgeMin < 1 {
+			errs = append(errs, fmt.Errorf("streak_nudge_min (STREAK_NUDGE_MIN) must be at least 1, got %d", c.StreakNudgeMin))
+		}
+	}
+
+	if c.DecayHalfLife < 0 {
+		errs = append(errs, errors.New("decay_half_life (DECAY_HALF_LIFE) must not be negative"))
+	}
+	if c.DecayRate < 0 || c.DecayRate >= 1 {
+		errs = append(errs, fmt.Errorf("decay_rate (DECAY_RATE) must be at least 0 and less than 1, got %v", c.DecayRate))
+	}
+	if c.DecayRate > 0 && !slices.Contains(digestPeriods, c.DecayPeriod) {
+		errs = append(errs, fmt.Errorf("decay_period (DECAY_PERIOD) must be one of %s, got %q", strings.Join(digestPeriods, ", "), c.DecayPeriod))
+	}
+	if c.DecayHalfLife > 0 && c.DecayRate > 0 {
+		errs = append(errs, errors.New("decay_half_life (DECAY_HALF_LIFE) and decay_rate (DECAY_RATE) cannot be used together"))
+	}
+
+	badgeIDs := map[string]bool{}
+	for i, badge := range c.Badges {
+		if badge.ID == "" {
+			errs = append(errs, fmt.Errorf("badges[%d].id is required", i))
+		} else if badgeIDs[badge.ID] {
+			errs = append(errs, fmt.Errorf("badges[%d].id %q is used by another badge", i, badge.ID))
+		}
+		badgeIDs[badge.ID] = true
+		if badge.Name == "" {
+			errs = append(errs, fmt.Errorf("badges[%d].name is required", i))
+		}
+		if !slices.Contains(badgeRules, badge.Rule) {
+			errs = append(errs, fmt.Errorf("badges[%d].rule must be one of %s, got %q", i, strings.Join(badgeRules, ", "), badge.Rule))
+		}
+		if badge.Threshold < 1 {
+			errs = append(errs, fmt.Errorf("badges[%d].threshold must be at least 1, got %d", i, badge.Threshold))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Location returns the time zone period boundaries are computed in, or UTC if it is not valid
+func (c *Config) Location() *time.Location {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// ValidateSlack checks the settings needed to connect to Slack
+func (c *Config) ValidateSlack() error {
+	var errs []error
+
+	if c.SlackBotToken == "" {
+		errs = append(errs, errors.New("slack_bot_token (SLACK_BOT_TOKEN) is required"))
+	}
+	if c.SlackAppToken == "" {
+		errs = append(errs, errors.New("slack_app_token (SLACK_APP_TOKEN) is required"))
+	}
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	for _, key := range []string{"SLACK_BOT_TOKEN", "SLACK_APP_TOKEN", "DEBUG", "REPOSITORY_TYPE", "DATABASE_URL", "DYNAMO_USER_POINTS_TABLE", "DYNAMO_LOCAL", "DYNAMO_CREATE_TABLES", "AUDIT_LOG_PATH", "MESSAGES_PATH", "MESSAGES_RELOAD_INTERVAL", "LOCALE", "USE_USER_LOCALE", "RICH_REPLIES", "MILESTONES", "SHAME_MILESTONES", "MILESTONE_CHANNEL", "REPLY_MODE", "NOTIFICATION_DELAY", "CHANNEL_ALLOWLIST", "BOT_MANAGERS", "DISABLE_MINUS", "FLOOR_AT_ZERO", "SELF_VOTE", "COLLUSION_THRESHOLD", "COLLUSION_WINDOW", "COLLUSION_DAMPEN", "COLLUSION_REPORT_USERS", "TIMEZONE", "DIGEST_CHANNEL", "DIGEST_SCHEDULE", "DIGEST_PERIOD", "SEASON_SCHEDULE", "SEASON_CHANNEL", "DECAY_HALF_LIFE", "DECAY_RATE", "DECAY_PERIOD", "STREAK_NUDGE_SCHEDULE", "STREAK_NUDGE_MIN"} {
+		t.Setenv(key, "")
+	}
+
+	path := writeConfigFile(t, `
+slack_bot_token: xoxb-file
+slack_app_token: xapp-file
+repository_type: dynamodb
+dynamodb_table: points
+debug: true
+messages_reload_interval: 1m
+`)
+
+	t.Run("file only", func(t *testing.T) {
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.SlackBotToken != "xoxb-file" || cfg.RepositoryType != DynamoDBRepository || cfg.DynamoDBTableName != "points" || !cfg.Debug {
+			t.Errorf("Load() = %+v", cfg)
+		}
+		if cfg.MessagesReloadInterval != time.Minute {
+			t.Errorf("Load() MessagesReloadInterval = %v, want 1m", cfg.MessagesReloadInterval)
+		}
+		if cfg.AuditLogPath != "plusplusbot-audit.log" {
+			t.Errorf("Load() AuditLogPath = %q, want the default", cfg.AuditLogPath)
+		}
+		if cfg.DynamoDBCreateTables {
+			t.Error("Load() DynamoDBCreateTables = true, want tables not created by default")
+		}
+		if len(cfg.Milestones) != 0 {
+			t.Errorf("Load() Milestones = %v, want none by default", cfg.Milestones)
+		}
+	})
+
+	t.Run("environment overrides file", func(t *testing.T) {
+		t.Setenv("SLACK_BOT_TOKEN", "xoxb-env")
+		t.Setenv("REPOSITORY_TYPE", "sqlite")
+		t.Setenv("DATABASE_URL", "env.db")
+		t.Setenv("SHAME_MILESTONES", "-10, -100")
+		t.Setenv("BOT_MANAGERS", "U1, U2")
+		t.Setenv("COLLUSION_THRESHOLD", "3")
+		t.Setenv("DIGEST_CHANNEL", "C123")
+		t.Setenv("DIGEST_PERIOD", "month")
+		t.Setenv("DECAY_RATE", "0.1")
+		t.Setenv("DEBUG", "false")
+		t.Setenv("FLOOR_AT_ZERO", "1")
+		t.Setenv("DYNAMO_CREATE_TABLES", "true")
+
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.SlackBotToken != "xoxb-env" || cfg.SlackAppToken != "xapp-file" || cfg.RepositoryType != SQLiteRepository || cfg.SQLiteDBPath != "env.db" {
+			t.Errorf("Load() = %+v", cfg)
+		}
+		if !slices.Equal(cfg.ShameMilestones, []int{-10, -100}) {
+			t.Errorf("Load() ShameMilestones = %v, want [-10 -100]", cfg.ShameMilestones)
+		}
+		if !slices.Equal(cfg.BotManagers, []string{"U1", "U2"}) {
+			t.Errorf("Load() BotManagers = %v, want [U1 U2]", cfg.BotManagers)
+		}
+		if cfg.CollusionThreshold != 3 || cfg.CollusionWindow != 24*time.Hour {
+			t.Errorf("Load() CollusionThreshold, CollusionWindow = %d, %v, want 3, 24h", cfg.CollusionThreshold, cfg.CollusionWindow)
+		}
+		if want := []Digest{{Schedule: "0 9 * * 1", Period: "month", Channel: "C123"}}; !slices.Equal(cfg.Digests, want) {
+			t.Errorf("Load() Digests = %+v, want %+v", cfg.Digests, want)
+		}
+		if cfg.DecayRate != 0.1 {
+			t.Errorf("Load() DecayRate = %v, want 0.1", cfg.DecayRate)
+		}
+		if cfg.Debug || !cfg.FloorAtZero {
+			t.Errorf("Load() Debug, FloorAtZero = %v, %v, want false, true", cfg.Debug, cfg.FloorAtZero)
+		}
+		if !cfg.DynamoDBCreateTables {
+			t.Error("Load() DynamoDBCreateTables = false, want true")
+		}
+	})
+
+	t.Run("no file", func(t *testing.T) {
+		cfg, err := Load("")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if cfg.RepositoryType != SQLiteRepository || cfg.DynamoDBTableName != "user_points" {
+			t.Errorf("Load() = %+v, want defaults", cfg)
+		}
+		if cfg.CollusionThreshold != 0 {
+			t.Errorf("Load() CollusionThreshold = %d, want 0 (disabled)", cfg.CollusionThreshold)
+		}
+	})
+
+	t.Run("invalid environment variable", func(t *testing.T) {
+		t.Setenv("MESSAGES_RELOAD_INTERVAL", "often")
+		t.Setenv("DISABLE_MINUS", "yes please")
+		_, err := Load(path)
+		if err == nil || !strings.Contains(err.Error(), "MESSAGES_RELOAD_INTERVAL") || !strings.Contains(err.Error(), "DISABLE_MINUS") {
+			t.Errorf("Load() error = %v, want errors about MESSAGES_RELOAD_INTERVAL and DISABLE_MINUS", err)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		if _, err := Load(writeConfigFile(t, "repository_tpye: sqlite\n")); err == nil {
+			t.Error("Load() with an unknown key should fail")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+			t.Error("Load() with a missing file should fail")
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		wantErrs []string
+	}{
+		{
+			name:   "valid sqlite",
+			config: Config{RepositoryType: SQLiteRepository, SQLiteDBPath: "plusplus.db", AuditLogPath: "audit.log"},
+		},
+		{
+			name:   "valid dynamodb",
+			config: Config{RepositoryType: DynamoDBRepository, DynamoDBTableName: "user_points", AuditLogPath: "audit.log"},
+		},
+		{
+			name:     "sqlite without path",
+			config:   Config{RepositoryType: SQLiteRepository, AuditLogPath: "audit.log"},
+			wantErrs: []string{"database_url"},
+		},
+		{
+			name:     "both kinds of decay",
+			config:   Config{RepositoryType: SQLiteRepository, SQLiteDBPath: "plusplus.db", AuditLogPath: "audit.log", DecayHalfLife: time.Hour, DecayRate: 0.1, DecayPeriod: "month"},
+			wantErrs: []string{"decay_half_life"},
+		},
+		{
+			name:     "multiple problems",
+			config:   Config{RepositoryType: "postgres", Locale: "japanese!", ChannelLocales: map[string]string{"C123": "x"}, NotificationDelay: -time.Second, Milestones: []int{0}, ShameMilestones: []int{10}, ReplyMode: "loud", ChannelReplyModes: map[string]string{"C456": "quiet"}, SelfVote: "shrug", CollusionThreshold: 3, Timezone: "Mars/Olympus", Digests: []Digest{{Schedule: "0 9 * *", Period: "year"}}, SeasonSchedule: "quarterly", StreakNudgeSchedule: "0 16 * * *", DecayRate: 1.5, Badges: []Badge{{ID: "a", Name: "A", Rule: "karma", Threshold: 0}, {ID: "a"}}},
+			wantErrs: []string{"self_vote", "collusion_window", "timezone", "digests[0].schedule", "digests[0].period", "digests[0].channel", "season_schedule", "streak_nudge_min", "decay_rate", "decay_period", "badges[0].rule", "badges[0].threshold", "badges[1].id", "badges[1].name", "repository_type", "audit_log_path", "locale", "channel_locales.C123", "milestones", "shame_milestones", "reply_mode", "channel_reply_modes.C456", "notification_delay"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() error = nil, want %v", tt.wantErrs)
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSlack(t *testing.T) {
+	cfg := Config{}
+	err := cfg.ValidateSlack()
+	if err == nil || !strings.Contains(err.Error(), "slack_bot_token") || !strings.Contains(err.Error(), "slack_app_token") {
+		t.Errorf("ValidateSlack() error = %v, want both tokens reported", err)
+	}
+
+	cfg = Config{SlackBotToken: "xoxb", SlackAppToken: "xapp"}
+	if err := cfg.ValidateSlack(); err != nil {
+		t.Errorf("ValidateSlack() error = %v, want nil", err)
+	}
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -12,17 +13,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/guregu/dynamo/v2"
 )
 
-// UserPoints represents a user's points in DynamoDB
-type UserPoints struct {
-	UserID       string    `dynamo:"user_id,hash"`
-	Points       int       `dynamo:"points"`
-	IsUser       bool      `dynamo:"is_user"`
-	LastModified time.Time `dynamo:"last_modified"`
-}
-
 // DynamoDBRepository implements the UserPointsRepository interface using DynamoDB
 type DynamoDBRepository struct {
 	db        *dynamo.DB
@@ -30,8 +24,9 @@ type DynamoDBRepository struct {
 	logger    *slog.Logger
 }
 
-// NewDynamoDBRepository creates a new DynamoDBRepository instance
-func NewDynamoDBRepository(tableName string, isLocal bool, logger *slog.Logger) (*DynamoDBRepository, error) {
+// NewDynamoDBRepository creates a new DynamoDBRepository instance. Missing tables are created on DynamoDB Local,
+// and on AWS only if createTables is set.
+func NewDynamoDBRepository(tableName string, isLocal, createTables bool, logger *slog.Logger) (*DynamoDBRepository, error) {
 	var db *dynamo.DB
 
 	if isLocal {
@@ -47,11 +42,6 @@ func NewDynamoDBRepository(tableName string, isLocal bool, logger *slog.Logger)
 		db = dynamo.New(cfg, func(o *dynamodb.Options) {
 			o.BaseEndpoint = aws.String("http://localhost:8000")
 		})
-
-		err = setupDynamoDBSchema(db, tableName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to setup schema: %v", err)
-		}
 	} else {
 		logger.Info("Using AWS DynamoDB service")
 		cfg, err := config.LoadDefaultConfig(context.TODO())
@@ -67,6 +57,12 @@ func NewDynamoDBRepository(tableName string, isLocal bool, logger *slog.Logger)
 		db = dynamo.New(cfg)
 	}
 
+	if isLocal || createTables {
+		if err := setupDynamoDBSchema(db, tableName, isLocal); err != nil {
+			return nil, fmt.Errorf("failed to setup schema: %v", err)
+		}
+	}
+
 	return &DynamoDBRepository{
 		db:        db,
 		tableName: tableName,
@@ -74,49 +70,255 @@ func NewDynamoDBRepository(tableName string, isLocal bool, logger *slog.Logger)
 	}, nil
 }
 
-// setupDynamoDBSchema creates the DynamoDB table if it doesn't exist
-func setupDynamoDBSchema(db *dynamo.DB, tableName string) error {
+// settingsTableName returns the name of the table user settings are stored in
+func settingsTableName(tableName string) string {
+	return tableName + "_settings"
+}
+
+// channelsTableName returns the name of the table channel settings are stored in
+func channelsTableName(tableName string) string {
+	return tableName + "_channels"
+}
+
+// eventsTableName returns the name of the table point events are stored in
+func eventsTableName(tableName string) string {
+	return tableName + "_events"
+}
+
+// giversTableName returns the name of the table giver counters are stored in
+func giversTableName(tableName string) string {
+	return tableName + "_givers"
+}
+
+// givenTableName returns the name of the table the points given by each giver to each target are stored in
+func givenTableName(tableName string) string {
+	return tableName + "_given"
+}
+
+// periodsTableName returns the name of the table per-period point counters are stored in
+func periodsTableName(tableName string) string {
+	return tableName + "_periods"
+}
+
+// dynamoPeriodPoints is the item counting the points a target received in a bucket: an hour, a day or a month in UTC
+type dynamoPeriodPoints struct {
+	Bucket string `dynamo:"bucket,hash"`
+	Target string `dynamo:"target,range"`
+	Points int    `dynamo:"points"`
+}
+
+// Formats of the buckets of per-period point counters
+const (
+	hourBucketFormat  = "hour#2006-01-02T15"
+	dayBucketFormat   = "day#2006-01-02"
+	monthBucketFormat = "month#2006-01"
+)
+
+// periodBuckets returns the buckets counting a point change made at t
+func periodBuckets(t time.Time) []string {
+	t = t.UTC()
+	return []string{t.Format(hourBucketFormat), t.Format(dayBucketFormat), t.Format(monthBucketFormat)}
+}
+
+// timeWindow is a part of a window of time, from from to to, excluding to
+type timeWindow struct {
+	from, to time.Time
+}
+
+// windowBuckets splits a window into the buckets it fully covers, the largest first, and the parts shorter than
+// an hour at its ends, which have to be summed from the point events
+func windowBuckets(from, to time.Time) ([]string, []timeWindow) {
+	from, to = from.UTC(), to.UTC()
+	start, end := from.Truncate(time.Hour), to.Truncate(time.Hour)
+	if start.Before(from) {
+		start = start.Add(time.Hour)
+	}
+	if !start.Before(end) {
+		if from.Before(to) {
+			return nil, []timeWindow{{from, to}}
+		}
+		return nil, nil
+	}
+
+	var edges []timeWindow
+	if from.Before(start) {
+		edges = append(edges, timeWindow{from, start})
+	}
+	if end.Before(to) {
+		edges = append(edges, timeWindow{end, to})
+	}
+
+	var buckets []string
+	for t := start; t.Before(end); {
+		midnight := t.Hour() == 0
+		switch {
+		case midnight && t.Day() == 1 && !t.AddDate(0, 1, 0).After(end):
+			buckets = append(buckets, t.Format(monthBucketFormat))
+			t = t.AddDate(0, 1, 0)
+		case midnight && !t.AddDate(0, 0, 1).After(end):
+			buckets = append(buckets, t.Format(dayBucketFormat))
+			t = t.AddDate(0, 0, 1)
+		default:
+			buckets = append(buckets, t.Format(hourBucketFormat))
+			t = t.Add(time.Hour)
+		}
+	}
+	return buckets, edges
+}
+
+// seasonsTableName returns the name of the table seasons are stored in
+func seasonsTableName(tableName string) string {
+	return tableName + "_seasons"
+}
+
+// standingsTableName returns the name of the table the final standings of seasons are stored in
+func standingsTableName(tableName string) string {
+	return tableName + "_standings"
+}
+
+// badgesTableName returns the name of the table the badges awarded to users are stored in
+func badgesTableName(tableName string) string {
+	return tableName + "_badges"
+}
+
+// streaksTableName returns the name of the table the giving streaks of users are stored in
+func streaksTableName(tableName string) string {
+	return tableName + "_streaks"
+}
+
+// dynamoSeasonStanding is the item holding the final points of a target in a season
+type dynamoSeasonStanding struct {
+	Season string `dynamo:"season,hash"`
+	UserID string `dynamo:"user_id,range"`
+	Points int    `dynamo:"points"`
+	IsUser bool   `dynamo:"is_user"`
+}
+
+// dynamoGiverStats is the item holding the counters of a giver, with the set of its recipients
+type dynamoGiverStats struct {
+	UserID     string    `dynamo:"user_id,hash"`
+	Given      int       `dynamo:"given"`
+	Recipients []string  `dynamo:"recipients,set"`
+	LastGiven  time.Time `dynamo:"last_given"`
+}
+
+// dynamoGivenPoints is the item counting the points a giver has given to a target
+type dynamoGivenPoints struct {
+	Giver     string `dynamo:"giver,hash"`
+	Recipient string `dynamo:"recipient,range"`
+	Points    int    `dynamo:"points"`
+}
+
+// stats converts the item to GiverStats
+func (s dynamoGiverStats) stats() GiverStats {
+	return GiverStats{
+		UserID:     s.UserID,
+		Given:      s.Given,
+		Recipients: len(s.Recipients),
+		LastGiven:  s.LastGiven,
+	}
+}
+
+// dynamoDBTables returns the tables of the repository and the record types stored in them
+func dynamoDBTables(tableName string) map[string]interface{} {
+	return map[string]interface{}{
+		tableName:                     UserPoints{},
+		settingsTableName(tableName):  UserSettings{},
+		channelsTableName(tableName):  ChannelSettings{},
+		eventsTableName(tableName):    PointEvent{},
+		giversTableName(tableName):    dynamoGiverStats{},
+		givenTableName(tableName):     dynamoGivenPoints{},
+		periodsTableName(tableName):   dynamoPeriodPoints{},
+		seasonsTableName(tableName):   Season{},
+		standingsTableName(tableName): dynamoSeasonStanding{},
+		badgesTableName(tableName):    UserBadge{},
+		streaksTableName(tableName):   GivingStreak{},
+	}
+}
+
+// setupDynamoDBSchema creates the DynamoDB tables if they don't exist. Tables are created with provisioned
+// capacity on DynamoDB Local, and with on-demand capacity on AWS.
+func setupDynamoDBSchema(db *dynamo.DB, tableName string, isLocal bool) error {
+	for name, from := range dynamoDBTables(tableName) {
+		if err := createDynamoDBTable(db, name, from, !isLocal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createDynamoDBTable creates a table for the given record type if it doesn't exist, and waits until it is active
+func createDynamoDBTable(db *dynamo.DB, tableName string, from interface{}, onDemand bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
 	t := db.Table(tableName)
-	_, err := t.Describe().Run(context.TODO())
-	if err != nil {
-		input := db.CreateTable(tableName, UserPoints{}).
-			Provision(10, 10)
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	_, err := t.Describe().Run(ctx)
+	var notFound *types.ResourceNotFoundException
+	if err == nil {
+		return nil
+	} else if !errors.As(err, &notFound) {
+		return fmt.Errorf("failed to describe table %s: %w", tableName, err)
+	}
 
-		return input.Run(ctx)
+	input := db.CreateTable(tableName, from)
+	if onDemand {
+		input = input.OnDemand(true)
+	} else {
+		input = input.Provision(10, 10)
+	}
+	var inUse *types.ResourceInUseException
+	if err := input.Run(ctx); err != nil && !errors.As(err, &inUse) {
+		return fmt.Errorf("failed to create table %s: %w", tableName, err)
+	}
+	if err := t.Wait(ctx); err != nil {
+		return fmt.Errorf("failed to wait for table %s: %w", tableName, err)
 	}
 	return nil
 }
 
-// AddPoints adds points to a user
+// AddPoints adds points to a user in one update. When the context floors totals, the update is conditioned
+// on the total the points were reduced for, and retried if the total changed in between.
 func (r *DynamoDBRepository) AddPoints(ctx context.Context, userID string, points int, isUser bool) error {
-	// First try to get the current user points
-	var userPoints UserPoints
-	err := r.db.Table(r.tableName).Get("user_id", userID).One(ctx, &userPoints)
+	for {
+		update := r.db.Table(r.tableName).Update("user_id", userID).
+			Set("is_user", isUser).
+			Set("last_modified", time.Now())
+		if !floorAtZero(ctx) {
+			return update.Add("points", points).Run(ctx)
+		}
 
-	if err != nil {
-		if err != dynamo.ErrNotFound {
+		current, exists, err := r.currentPoints(ctx, userID)
+		if err != nil {
 			return err
 		}
-
-		// User not found, create new record
-		userPoints = UserPoints{
-			UserID:       userID,
-			Points:       points,
-			IsUser:       isUser,
-			LastModified: time.Now(),
+		err = ifPointsUnchanged(update, current, exists).Add("points", flooredPoints(current, points)).Run(ctx)
+		if !dynamo.IsCondCheckFailed(err) {
+			return err
 		}
-
-		return r.db.Table(r.tableName).Put(userPoints).Run(ctx)
 	}
+}
 
-	// Update existing user
-	userPoints.Points += points
-	userPoints.IsUser = isUser
-	userPoints.LastModified = time.Now()
+// currentPoints reads the total of a user with a strongly consistent read, and whether it has a record
+func (r *DynamoDBRepository) currentPoints(ctx context.Context, userID string) (int, bool, error) {
+	var userPoints UserPoints
+	err := r.db.Table(r.tableName).Get("user_id", userID).Consistent(true).One(ctx, &userPoints)
+	if err == dynamo.ErrNotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return userPoints.Points, true, nil
+}
 
-	return r.db.Table(r.tableName).Put(userPoints).Run(ctx)
+// ifPointsUnchanged conditions an update of a points record on its total still being current,
+// or on the record still not existing
+func ifPointsUnchanged(update *dynamo.Update, current int, exists bool) *dynamo.Update {
+	if !exists {
+		return update.If("attribute_not_exists('user_id')")
+	}
+	return update.If("'points' = ?", current)
 }
 
 // GetPoints gets the current points for a user
@@ -134,6 +336,592 @@ func (r *DynamoDBRepository) GetPoints(ctx context.Context, userID string) (int,
 	return userPoints.Points, nil
 }
 
+// GetUserPoints gets the points record for a user
+func (r *DynamoDBRepository) GetUserPoints(ctx context.Context, userID string) (*UserPoints, error) {
+	var userPoints UserPoints
+	err := r.db.Table(r.tableName).Get("user_id", userID).One(ctx, &userPoints)
+	if err != nil {
+		if err == dynamo.ErrNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &userPoints, nil
+}
+
+// SetPoints sets the points of a user to the given value
+func (r *DynamoDBRepository) SetPoints(ctx context.Context, userID string, points int, isUser bool) error {
+	userPoints := UserPoints{
+		UserID:       userID,
+		Points:       points,
+		IsUser:       isUser,
+		LastModified: time.Now(),
+	}
+
+	return r.db.Table(r.tableName).Put(userPoints).Run(ctx)
+}
+
+// DeletePoints deletes the points record for a user
+func (r *DynamoDBRepository) DeletePoints(ctx context.Context, userID string) error {
+	return r.db.Table(r.tableName).Delete("user_id", userID).Run(ctx)
+}
+
+// ListPoints lists points records ordered by points, highest first
+func (r *DynamoDBRepository) ListPoints(ctx context.Context, limit int) ([]UserPoints, error) {
+	var records []UserPoints
+	if err := r.db.Table(r.tableName).Scan().All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	sortUserPoints(records)
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// GetRank gets the leaderboard position of a user by scanning the whole table
+func (r *DynamoDBRepository) GetRank(ctx context.Context, userID string) (int, error) {
+	userPoints, err := r.GetUserPoints(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	rank := 1
+	err = r.ScanPoints(ctx, func(other UserPoints) error {
+		if other.Points > userPoints.Points {
+			rank++
+		}
+		return nil
+	})
+	return rank, err
+}
+
+// ScanPoints calls fn for every points record, fetching them page by page
+func (r *DynamoDBRepository) ScanPoints(ctx context.Context, fn func(UserPoints) error) error {
+	iter := r.db.Table(r.tableName).Scan().Iter()
+
+	var userPoints UserPoints
+	for iter.Next(ctx, &userPoints) {
+		if err := fn(userPoints); err != nil {
+			return err
+		}
+		userPoints = UserPoints{}
+	}
+	return iter.Err()
+}
+
+// PutUserPoints stores a points record as is
+func (r *DynamoDBRepository) PutUserPoints(ctx context.Context, userPoints UserPoints) error {
+	if userPoints.LastModified.IsZero() {
+		userPoints.LastModified = time.Now()
+	}
+
+	return r.db.Table(r.tableName).Put(userPoints).Run(ctx)
+}
+
+// MovePoints stores the points record to and deletes the points record of from in one transaction
+func (r *DynamoDBRepository) MovePoints(ctx context.Context, from string, to UserPoints) error {
+	to.LastModified = time.Now()
+	table := r.db.Table(r.tableName)
+	return r.db.WriteTx().
+		Put(table.Put(to)).
+		Delete(table.Delete("user_id", from)).
+		Run(ctx)
+}
+
+// GetUserSettings gets the preferences of a user
+func (r *DynamoDBRepository) GetUserSettings(ctx context.Context, userID string) (*UserSettings, error) {
+	var settings UserSettings
+	err := r.db.Table(settingsTableName(r.tableName)).Get("user_id", userID).One(ctx, &settings)
+	if err != nil {
+		if err == dynamo.ErrNotFound {
+			return &UserSettings{UserID: userID}, nil
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// PutUserSettings stores the preferences of a user
+func (r *DynamoDBRepository) PutUserSettings(ctx context.Context, settings UserSettings) error {
+	return r.db.Table(settingsTableName(r.tableName)).Put(settings).Run(ctx)
+}
+
+// GetChannelSettings gets the settings of a channel
+func (r *DynamoDBRepository) GetChannelSettings(ctx context.Context, channelID string) (*ChannelSettings, error) {
+	var settings ChannelSettings
+	err := r.db.Table(channelsTableName(r.tableName)).Get("channel_id", channelID).One(ctx, &settings)
+	if err != nil {
+		if err == dynamo.ErrNotFound {
+			return &ChannelSettings{ChannelID: channelID}, nil
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// PutChannelSettings stores the settings of a channel
+func (r *DynamoDBRepository) PutChannelSettings(ctx context.Context, settings ChannelSettings) error {
+	return r.db.Table(channelsTableName(r.tableName)).Put(settings).Run(ctx)
+}
+
+// AddPointEvent records a point change in the history, adding it to the per-period counters of the target
+// in the same transaction
+func (r *DynamoDBRepository) AddPointEvent(ctx context.Context, event PointEvent) error {
+	err := r.pointEventTx(event).Run(ctx)
+	if dynamo.IsCondCheckFailed(err) {
+		// The event is already recorded
+		return nil
+	}
+	return err
+}
+
+// AddPointsWithEvent adds the delta of an event to the points of its target, records the event and adds it
+// to the per-period counters of the target in one transaction. The transaction is conditioned on the total
+// the delta was computed from, reduced when the context floors totals, and retried if the total changed.
+func (r *DynamoDBRepository) AddPointsWithEvent(ctx context.Context, event PointEvent, isUser bool) (PointChange, error) {
+	for {
+		current, exists, err := r.currentPoints(ctx, event.Target)
+		if err != nil {
+			return PointChange{}, err
+		}
+		applied := event
+		if floorAtZero(ctx) {
+			applied.Delta = flooredPoints(current, event.Delta)
+		}
+		update := r.db.Table(r.tableName).Update("user_id", event.Target).
+			Add("points", applied.Delta).
+			Set("is_user", isUser).
+			Set("last_modified", time.Now())
+		err = r.pointEventTx(applied).Update(ifPointsUnchanged(update, current, exists)).Run(ctx)
+		if err == nil {
+			return PointChange{Delta: applied.Delta, Points: current + applied.Delta}, nil
+		}
+		if !dynamo.IsCondCheckFailed(err) {
+			return PointChange{}, err
+		}
+
+		// Either the event is already recorded, and its points with it, or the total changed
+		recorded, err := r.recordedPointEvent(ctx, event)
+		if err != nil {
+			return PointChange{}, err
+		}
+		if recorded != nil {
+			points, _, err := r.currentPoints(ctx, event.Target)
+			return PointChange{Delta: recorded.Delta, Points: points}, err
+		}
+	}
+}
+
+// recordedPointEvent reads an event as recorded with a strongly consistent read, or nil if it is not recorded
+func (r *DynamoDBRepository) recordedPointEvent(ctx context.Context, event PointEvent) (*PointEvent, error) {
+	var recorded PointEvent
+	err := r.db.Table(eventsTableName(r.tableName)).Get("target", event.Target).
+		Range("timestamp", dynamo.Equal, event.Timestamp.UTC()).
+		Consistent(true).
+		One(ctx, &recorded)
+	if err == dynamo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &recorded, nil
+}
+
+// pointEventTx creates a transaction recording an event, unless it is already recorded, and adding it to the
+// per-period counters of its target
+func (r *DynamoDBRepository) pointEventTx(event PointEvent) *dynamo.WriteTx {
+	event.Timestamp = event.Timestamp.UTC()
+	tx := r.db.WriteTx()
+	tx.Put(r.db.Table(eventsTableName(r.tableName)).Put(event).If("attribute_not_exists('target')"))
+	// Changes of zero points are counted too, so that the counters list every target with events in a bucket
+	periods := r.db.Table(periodsTableName(r.tableName))
+	for _, bucket := range periodBuckets(event.Timestamp) {
+		tx.Update(periods.Update("bucket", bucket).Range("target", event.Target).Add("points", event.Delta))
+	}
+	return tx
+}
+
+// ListPointEvents lists the point changes received by a target since the given time.
+// Events of a single target are queried. Events of every target are queried for the targets the per-period
+// counters list since the given time, and scanned only when listing the whole history, from the zero time.
+func (r *DynamoDBRepository) ListPointEvents(ctx context.Context, target string, since time.Time) ([]PointEvent, error) {
+	table := r.db.Table(eventsTableName(r.tableName))
+	var events []PointEvent
+	switch {
+	case target != "":
+		if err := table.Get("target", target).Range("timestamp", dynamo.GreaterOrEqual, since.UTC()).All(ctx, &events); err != nil {
+			return nil, err
+		}
+	case since.IsZero():
+		if err := table.Scan().All(ctx, &events); err != nil {
+			return nil, err
+		}
+	default:
+		// The window ends after the current hour, so that events added while listing are not cut in half
+		targets, err := r.windowTargets(ctx, since, time.Now().UTC().Truncate(time.Hour).Add(time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range targets {
+			var received []PointEvent
+			if err := table.Get("target", target).Range("timestamp", dynamo.GreaterOrEqual, since.UTC()).All(ctx, &received); err != nil {
+				return nil, err
+			}
+			events = append(events, received...)
+		}
+	}
+
+	sortPointEvents(events)
+	return events, nil
+}
+
+// windowTargets returns the targets with a counter in the buckets covering a window, which are the only
+// targets that can have point events in it
+func (r *DynamoDBRepository) windowTargets(ctx context.Context, from, to time.Time) ([]string, error) {
+	buckets, edges := windowBuckets(from, to)
+	var targets []string
+	seen := map[string]bool{}
+	for _, bucket := range buckets {
+		var items []dynamoPeriodPoints
+		if err := r.db.Table(periodsTableName(r.tableName)).Get("bucket", bucket).All(ctx, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if !seen[item.Target] {
+				seen[item.Target] = true
+				targets = append(targets, item.Target)
+			}
+		}
+	}
+	for _, edge := range edges {
+		edgeTargets, err := r.hourTargets(ctx, edge)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range edgeTargets {
+			if !seen[target] {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// ScanPointEvents calls fn for every point change, fetching them page by page
+func (r *DynamoDBRepository) ScanPointEvents(ctx context.Context, fn func(PointEvent) error) error {
+	iter := r.db.Table(eventsTableName(r.tableName)).Scan().Iter()
+
+	var event PointEvent
+	for iter.Next(ctx, &event) {
+		if err := fn(event); err != nil {
+			return err
+		}
+		event = PointEvent{}
+	}
+	return iter.Err()
+}
+
+// GetPointsBetween sums the per-period counters of a target covering the window, and the point events
+// at its ends that are not aligned to an hour
+func (r *DynamoDBRepository) GetPointsBetween(ctx context.Context, target string, from, to time.Time) (int, error) {
+	buckets, edges := windowBuckets(from, to)
+	points := 0
+	if len(buckets) > 0 {
+		keys := make([]dynamo.Keyed, 0, len(buckets))
+		for _, bucket := range buckets {
+			keys = append(keys, dynamo.Keys{bucket, target})
+		}
+		var items []dynamoPeriodPoints
+		err := r.db.Table(periodsTableName(r.tableName)).Batch("bucket", "target").Get(keys...).All(ctx, &items)
+		if err != nil && err != dynamo.ErrNotFound {
+			return 0, err
+		}
+		for _, item := range items {
+			points += item.Points
+		}
+	}
+
+	for _, edge := range edges {
+		var events []PointEvent
+		err := r.db.Table(eventsTableName(r.tableName)).Get("target", target).
+			Range("timestamp", dynamo.GreaterOrEqual, edge.from).
+			Filter("'timestamp' < ?", edge.to).
+			All(ctx, &events)
+		if err != nil {
+			return 0, err
+		}
+		for _, event := range events {
+			points += event.Delta
+		}
+	}
+	return points, nil
+}
+
+// ListPointsBetween sums the per-period counters of every target covering the window, and the point events
+// at its ends that are not aligned to an hour, queried for the targets counted in the hours of the ends
+func (r *DynamoDBRepository) ListPointsBetween(ctx context.Context, from, to time.Time, limit int) ([]UserPoints, error) {
+	buckets, edges := windowBuckets(from, to)
+	totals := map[string]int{}
+	for _, bucket := range buckets {
+		var items []dynamoPeriodPoints
+		if err := r.db.Table(periodsTableName(r.tableName)).Get("bucket", bucket).All(ctx, &items); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			totals[item.Target] += item.Points
+		}
+	}
+	for _, edge := range edges {
+		targets, err := r.hourTargets(ctx, edge)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range targets {
+			var events []PointEvent
+			err := r.db.Table(eventsTableName(r.tableName)).Get("target", target).
+				Range("timestamp", dynamo.GreaterOrEqual, edge.from).
+				Filter("'timestamp' < ?", edge.to).
+				All(ctx, &events)
+			if err != nil {
+				return nil, err
+			}
+			for _, event := range events {
+				totals[event.Target] += event.Delta
+			}
+		}
+	}
+
+	var records []UserPoints
+	for target, points := range totals {
+		if points != 0 {
+			records = append(records, UserPoints{UserID: target, Points: points})
+		}
+	}
+	sortUserPoints(records)
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	// Whether a target is a user is kept in its points record
+	keys := make([]dynamo.Keyed, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, dynamo.Keys{record.UserID})
+	}
+	var stored []UserPoints
+	err := r.db.Table(r.tableName).Batch("user_id").Get(keys...).All(ctx, &stored)
+	if err != nil && err != dynamo.ErrNotFound {
+		return nil, err
+	}
+	isUser := make(map[string]bool, len(stored))
+	for _, userPoints := range stored {
+		isUser[userPoints.UserID] = userPoints.IsUser
+	}
+	for i := range records {
+		records[i].IsUser = isUser[records[i].UserID]
+	}
+	return records, nil
+}
+
+// hourTargets returns the targets with a counter in the hour buckets overlapping a window, which are
+// the only targets that can have point events in it
+func (r *DynamoDBRepository) hourTargets(ctx context.Context, window timeWindow) ([]string, error) {
+	var targets []string
+	seen := map[string]bool{}
+	for hour := window.from.Truncate(time.Hour); hour.Before(window.to); hour = hour.Add(time.Hour) {
+		var items []dynamoPeriodPoints
+		err := r.db.Table(periodsTableName(r.tableName)).Get("bucket", hour.Format(hourBucketFormat)).All(ctx, &items)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if !seen[item.Target] {
+				seen[item.Target] = true
+				targets = append(targets, item.Target)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// AddGivenPoints adds points given by a user to a target to the counters of the giver in one transaction
+func (r *DynamoDBRepository) AddGivenPoints(ctx context.Context, giver, recipient string, points int) error {
+	stats := r.db.Table(giversTableName(r.tableName)).Update("user_id", giver).
+		Add("given", points).
+		AddStringsToSet("recipients", recipient).
+		Set("last_given", time.Now().UTC())
+	given := r.db.Table(givenTableName(r.tableName)).Update("giver", giver).
+		Range("recipient", recipient).
+		Add("points", points)
+	return r.db.WriteTx().Update(stats).Update(given).Run(ctx)
+}
+
+// GetGiverStats gets the counters of a giver
+func (r *DynamoDBRepository) GetGiverStats(ctx context.Context, giver string) (*GiverStats, error) {
+	var item dynamoGiverStats
+	err := r.db.Table(giversTableName(r.tableName)).Get("user_id", giver).One(ctx, &item)
+	if err != nil {
+		if err == dynamo.ErrNotFound {
+			return &GiverStats{UserID: giver}, nil
+		}
+		return nil, err
+	}
+
+	stats := item.stats()
+	return &stats, nil
+}
+
+// ListGivers lists the counters of givers ordered by the points given, highest first
+func (r *DynamoDBRepository) ListGivers(ctx context.Context, limit int) ([]GiverStats, error) {
+	var items []dynamoGiverStats
+	if err := r.db.Table(giversTableName(r.tableName)).Scan().All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	stats := make([]GiverStats, 0, len(items))
+	for _, item := range items {
+		stats = append(stats, item.stats())
+	}
+	sortGiverStats(stats)
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats, nil
+}
+
+// ListGivenPoints lists the targets a giver has given points to with the points given to each, highest first
+func (r *DynamoDBRepository) ListGivenPoints(ctx context.Context, giver string, limit int) ([]UserPoints, error) {
+	var items []dynamoGivenPoints
+	if err := r.db.Table(givenTableName(r.tableName)).Get("giver", giver).All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	records := make([]UserPoints, 0, len(items))
+	for _, item := range items {
+		records = append(records, UserPoints{UserID: item.Recipient, Points: item.Points})
+	}
+	sortUserPoints(records)
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+// PutSeason stores a season, replacing the one with the same name
+func (r *DynamoDBRepository) PutSeason(ctx context.Context, season Season) error {
+	season.Start = season.Start.UTC()
+	season.End = season.End.UTC()
+	return r.db.Table(seasonsTableName(r.tableName)).Put(season).Run(ctx)
+}
+
+// ListSeasons lists the seasons ordered by their start, oldest first, by scanning the table
+func (r *DynamoDBRepository) ListSeasons(ctx context.Context) ([]Season, error) {
+	var seasons []Season
+	if err := r.db.Table(seasonsTableName(r.tableName)).Scan().All(ctx, &seasons); err != nil {
+		return nil, err
+	}
+
+	sortSeasons(seasons)
+	return seasons, nil
+}
+
+// PutSeasonStandings stores the final points of the targets in a season in batches
+func (r *DynamoDBRepository) PutSeasonStandings(ctx context.Context, season string, standings []UserPoints) error {
+	if len(standings) == 0 {
+		return nil
+	}
+
+	items := make([]interface{}, 0, len(standings))
+	for _, standing := range standings {
+		items = append(items, dynamoSeasonStanding{
+			Season: season,
+			UserID: standing.UserID,
+			Points: standing.Points,
+			IsUser: standing.IsUser,
+		})
+	}
+	_, err := r.db.Table(standingsTableName(r.tableName)).Batch("season", "user_id").Write().Put(items...).Run(ctx)
+	return err
+}
+
+// ListSeasonStandings lists the final points of the targets in a season ordered by points, highest first
+func (r *DynamoDBRepository) ListSeasonStandings(ctx context.Context, season string, limit int) ([]UserPoints, error) {
+	var items []dynamoSeasonStanding
+	if err := r.db.Table(standingsTableName(r.tableName)).Get("season", season).All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	standings := make([]UserPoints, 0, len(items))
+	for _, item := range items {
+		standings = append(standings, UserPoints{UserID: item.UserID, Points: item.Points, IsUser: item.IsUser})
+	}
+	sortUserPoints(standings)
+	if limit > 0 && len(standings) > limit {
+		standings = standings[:limit]
+	}
+	return standings, nil
+}
+
+// AddBadge awards a badge to a user and reports whether it is new, with a conditional put
+func (r *DynamoDBRepository) AddBadge(ctx context.Context, badge UserBadge) (bool, error) {
+	badge.AwardedAt = badge.AwardedAt.UTC()
+	err := r.db.Table(badgesTableName(r.tableName)).Put(badge).If("attribute_not_exists('badge')").Run(ctx)
+	if dynamo.IsCondCheckFailed(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListBadges lists the badges awarded to a user, oldest first
+func (r *DynamoDBRepository) ListBadges(ctx context.Context, userID string) ([]UserBadge, error) {
+	var badges []UserBadge
+	if err := r.db.Table(badgesTableName(r.tableName)).Get("user_id", userID).All(ctx, &badges); err != nil {
+		return nil, err
+	}
+
+	sortBadges(badges)
+	return badges, nil
+}
+
+// GetGivingStreak gets the giving streak of a user, all zero if there is none
+func (r *DynamoDBRepository) GetGivingStreak(ctx context.Context, userID string) (*GivingStreak, error) {
+	var streak GivingStreak
+	err := r.db.Table(streaksTableName(r.tableName)).Get("user_id", userID).One(ctx, &streak)
+	if err == dynamo.ErrNotFound {
+		return &GivingStreak{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &streak, nil
+}
+
+// PutGivingStreak stores the giving streak of a user
+func (r *DynamoDBRepository) PutGivingStreak(ctx context.Context, streak GivingStreak) error {
+	return r.db.Table(streaksTableName(r.tableName)).Put(streak).Run(ctx)
+}
+
+// ListGivingStreaks lists the giving streaks of every user ordered by user ID, by scanning the table
+func (r *DynamoDBRepository) ListGivingStreaks(ctx context.Context) ([]GivingStreak, error) {
+	var streaks []GivingStreak
+	if err := r.db.Table(streaksTableName(r.tableName)).Scan().All(ctx, &streaks); err != nil {
+		return nil, err
+	}
+
+	sortGivingStreaks(streaks)
+	return streaks, nil
+}
+
 // Close is a no-op for DynamoDB as it doesn't require explicit connection closing
 func (r *DynamoDBRepository) Close() error {
 	// DynamoDB doesn't require explicit connection closing
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
@@ -23,7 +25,7 @@ func setupTestDynamoDBRepository(t *testing.T) (*DynamoDBRepository, func()) {
 		Level: slog.LevelError,
 	}))
 
-	repo, err := NewDynamoDBRepository(tableName, true, logger)
+	repo, err := NewDynamoDBRepository(tableName, true, false, logger)
 	if err != nil {
 		t.Fatalf("Failed to create test repository: %v", err)
 	}
@@ -34,9 +36,10 @@ func setupTestDynamoDBRepository(t *testing.T) (*DynamoDBRepository, func()) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := repo.db.Table(tableName).DeleteTable().Run(ctx)
-		if err != nil {
-			t.Logf("Failed to delete test table: %v", err)
+		for name := range dynamoDBTables(tableName) {
+			if err := repo.db.Table(name).DeleteTable().Run(ctx); err != nil {
+				t.Logf("Failed to delete test table %s: %v", name, err)
+			}
 		}
 	}
 
@@ -137,6 +140,103 @@ func TestDynamoDBGetPoints(t *testing.T) {
 	}
 }
 
+func TestDynamoDBSetDeleteAndListPoints(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if err := repo.SetPoints(ctx, "user1", 7, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	if err := repo.AddPoints(ctx, "user2", 10, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+
+	record, err := repo.GetUserPoints(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUserPoints() error = %v", err)
+	}
+	if record.Points != 7 || !record.IsUser {
+		t.Errorf("GetUserPoints() = %+v, want 7 points for a user", record)
+	}
+
+	records, err := repo.ListPoints(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListPoints() error = %v", err)
+	}
+	if len(records) != 2 || records[0].UserID != "user2" || records[1].UserID != "user1" {
+		t.Errorf("ListPoints() = %+v, want user2 then user1", records)
+	}
+
+	if rank, err := repo.GetRank(ctx, "user1"); err != nil || rank != 2 {
+		t.Errorf("GetRank() = %d, %v, want 2", rank, err)
+	}
+
+	if err := repo.DeletePoints(ctx, "user1"); err != nil {
+		t.Fatalf("DeletePoints() error = %v", err)
+	}
+	if _, err := repo.GetUserPoints(ctx, "user1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserPoints() after DeletePoints() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDynamoDBMovePoints(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := repo.SetPoints(ctx, "user1", 3, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	if err := repo.SetPoints(ctx, "user2", 4, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+
+	if err := repo.MovePoints(ctx, "user1", UserPoints{UserID: "user2", Points: 7, IsUser: true}); err != nil {
+		t.Fatalf("MovePoints() error = %v", err)
+	}
+	if _, err := repo.GetUserPoints(ctx, "user1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserPoints() of the source error = %v, want ErrNotFound", err)
+	}
+	record, err := repo.GetUserPoints(ctx, "user2")
+	if err != nil {
+		t.Fatalf("GetUserPoints() error = %v", err)
+	}
+	if record.Points != 7 || !record.IsUser {
+		t.Errorf("GetUserPoints() of the destination = %+v, want 7 points for a user", record)
+	}
+}
+
+func TestDynamoDBScanAndPutPoints(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	lastModified := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+
+	for _, id := range []string{"user1", "user2", "user3"} {
+		if err := repo.PutUserPoints(ctx, UserPoints{UserID: id, Points: 1, IsUser: true, LastModified: lastModified}); err != nil {
+			t.Fatalf("PutUserPoints() error = %v", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	err := repo.ScanPoints(ctx, func(p UserPoints) error {
+		seen[p.UserID] = true
+		if !p.LastModified.Equal(lastModified) {
+			t.Errorf("ScanPoints() last modified = %v, want %v", p.LastModified, lastModified)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanPoints() error = %v", err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("ScanPoints() visited %d records, want 3", len(seen))
+	}
+}
+
 func TestDynamoDBTableCreation(t *testing.T) {
 	// Skip test if DYNAMO_LOCAL is not set
 	if os.Getenv("DYNAMO_LOCAL") == "" {
@@ -151,7 +251,7 @@ func TestDynamoDBTableCreation(t *testing.T) {
 		Level: slog.LevelError,
 	}))
 
-	repo, err := NewDynamoDBRepository(tableName, true, logger)
+	repo, err := NewDynamoDBRepository(tableName, true, false, logger)
 	if err != nil {
 		t.Fatalf("Failed to create test repository: %v", err)
 	}
@@ -160,9 +260,10 @@ func TestDynamoDBTableCreation(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		err := repo.db.Table(tableName).DeleteTable().Run(ctx)
-		if err != nil {
-			t.Logf("Failed to delete test table: %v", err)
+		for name := range dynamoDBTables(tableName) {
+			if err := repo.db.Table(name).DeleteTable().Run(ctx); err != nil {
+				t.Logf("Failed to delete test table %s: %v", name, err)
+			}
 		}
 	}()
 
@@ -170,8 +271,523 @@ func TestDynamoDBTableCreation(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	_, err = repo.db.Table(tableName).Describe().Run(ctx)
+	for name := range dynamoDBTables(tableName) {
+		if _, err := repo.db.Table(name).Describe().Run(ctx); err != nil {
+			t.Errorf("Table %s was not created: %v", name, err)
+		}
+	}
+}
+
+func TestDynamoDBUserSettings(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	settings, err := repo.GetUserSettings(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUserSettings() error = %v", err)
+	}
+	if settings.Notify {
+		t.Errorf("GetUserSettings() = %+v, want notifications off by default", settings)
+	}
+
+	if err := repo.PutUserSettings(ctx, UserSettings{UserID: "user1", Notify: true}); err != nil {
+		t.Fatalf("PutUserSettings() error = %v", err)
+	}
+	if settings, err := repo.GetUserSettings(ctx, "user1"); err != nil || !settings.Notify {
+		t.Errorf("GetUserSettings() = %+v, %v, want notifications on", settings, err)
+	}
+}
+
+func TestDynamoDBChannelSettings(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	settings, err := repo.GetChannelSettings(ctx, "C1")
+	if err != nil {
+		t.Fatalf("GetChannelSettings() error = %v", err)
+	}
+	if settings.ChannelID != "C1" || settings.Enabled != nil || settings.AllowMinus != nil || settings.ReplyMode != "" || settings.Locale != "" {
+		t.Errorf("GetChannelSettings() = %+v, want no fields set", settings)
+	}
+
+	disabled := false
+	if err := repo.PutChannelSettings(ctx, ChannelSettings{ChannelID: "C1", AllowMinus: &disabled, ReplyMode: "thread", Locale: "ja"}); err != nil {
+		t.Fatalf("PutChannelSettings() error = %v", err)
+	}
+	settings, err = repo.GetChannelSettings(ctx, "C1")
+	if err != nil {
+		t.Fatalf("GetChannelSettings() error = %v", err)
+	}
+	if settings.Enabled != nil || settings.AllowMinus == nil || *settings.AllowMinus || settings.ReplyMode != "thread" || settings.Locale != "ja" {
+		t.Errorf("GetChannelSettings() = %+v", settings)
+	}
+}
+
+func TestDynamoDBPointEvents(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+
+	events := []PointEvent{
+		{Target: "U2", Timestamp: start, Giver: "U1", Channel: "C1", Delta: 1, Reason: "for the review"},
+		{Target: "U1", Timestamp: start.Add(time.Millisecond), Giver: "U2", Channel: "C1", Delta: 1},
+		{Target: "U2", Timestamp: start.Add(time.Hour), Giver: "U1", Channel: "C2", Dampened: true},
+	}
+	for _, event := range events {
+		// Adding an event twice stores it once
+		for i := 0; i < 2; i++ {
+			if err := repo.AddPointEvent(ctx, event); err != nil {
+				t.Fatalf("AddPointEvent() error = %v", err)
+			}
+		}
+	}
+
+	got, err := repo.ListPointEvents(ctx, "", start)
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("ListPointEvents() = %+v, want %d events", got, len(events))
+	}
+	for i, want := range events {
+		if got[i].Target != want.Target || !got[i].Timestamp.Equal(want.Timestamp) || got[i].Giver != want.Giver || got[i].Channel != want.Channel || got[i].Delta != want.Delta || got[i].Dampened != want.Dampened || got[i].Reason != want.Reason {
+			t.Errorf("ListPointEvents()[%d] = %+v, want %+v", i, got[i], want)
+		}
+	}
+
+	// Listing every target from within an hour finds the targets through the hour counters
+	got, err = repo.ListPointEvents(ctx, "", start.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != 1 || !got[0].Dampened {
+		t.Errorf("ListPointEvents(since 30 minutes later) = %+v, want the dampened event", got)
+	}
+
+	got, err = repo.ListPointEvents(ctx, "U2", start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != 1 || !got[0].Dampened {
+		t.Errorf("ListPointEvents(U2, since a minute later) = %+v, want the dampened event", got)
+	}
+
+	count := 0
+	if err := repo.ScanPointEvents(ctx, func(PointEvent) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("ScanPointEvents() error = %v", err)
+	}
+	if count != len(events) {
+		t.Errorf("ScanPointEvents() visited %d events, want %d", count, len(events))
+	}
+}
+
+func TestDynamoDBPointsBetween(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	day := time.Date(2025, 5, 16, 0, 0, 0, 0, time.UTC)
+	for _, target := range []string{"U1", "U2"} {
+		if err := repo.AddPoints(ctx, target, 1, true); err != nil {
+			t.Fatalf("AddPoints() error = %v", err)
+		}
+	}
+	events := []PointEvent{
+		{Target: "U2", Timestamp: day.Add(12 * time.Hour), Giver: "U1", Delta: 1},
+		{Target: "U1", Timestamp: day.Add(12*time.Hour + 30*time.Minute), Giver: "U2", Delta: 1},
+		{Target: "U2", Timestamp: day.Add(24*time.Hour - 30*time.Second), Giver: "U3", Delta: 1},
+		{Target: "U2", Timestamp: day.Add(24*time.Hour + 10*time.Minute), Giver: "U3", Delta: -1},
+		{Target: "sake", Timestamp: time.Date(2025, 5, 31, 18, 0, 0, 0, time.UTC), Giver: "U1", Delta: 2},
+		{Target: "U1", Timestamp: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), Giver: "U2", Delta: 1},
+	}
+	for _, event := range events {
+		// Adding an event twice counts it once
+		for i := 0; i < 2; i++ {
+			if err := repo.AddPointEvent(ctx, event); err != nil {
+				t.Fatalf("AddPointEvent() error = %v", err)
+			}
+		}
+	}
+
+	may := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	pointsTests := []struct {
+		name     string
+		target   string
+		from, to time.Time
+		want     int
+	}{
+		{"day", "U2", day, day.AddDate(0, 0, 1), 2},
+		{"month", "U2", may, june, 1},
+		{"not aligned to hours", "U2", day.Add(11*time.Hour + 45*time.Minute), day.Add(24*time.Hour + 5*time.Minute), 2},
+		{"within an hour", "U1", day.Add(12*time.Hour + 15*time.Minute), day.Add(12*time.Hour + 45*time.Minute), 1},
+		{"no events", "U3", may, june, 0},
+	}
+	for _, tt := range pointsTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetPointsBetween(ctx, tt.target, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetPointsBetween() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetPointsBetween(%s, %v, %v) = %d, want %d", tt.target, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	records, err := repo.ListPointsBetween(ctx, may, june, 0)
+	if err != nil {
+		t.Fatalf("ListPointsBetween() error = %v", err)
+	}
+	want := []UserPoints{{UserID: "sake", Points: 2}, {UserID: "U1", Points: 1, IsUser: true}, {UserID: "U2", Points: 1, IsUser: true}}
+	if len(records) != len(want) {
+		t.Fatalf("ListPointsBetween() = %+v, want %+v", records, want)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("ListPointsBetween()[%d] = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+
+	records, err = repo.ListPointsBetween(ctx, day.AddDate(0, 0, 1), june.AddDate(0, 0, 1), 0)
+	if err != nil {
+		t.Fatalf("ListPointsBetween() error = %v", err)
+	}
+	if len(records) != 3 || records[0].UserID != "sake" || records[2].UserID != "U2" || records[2].Points != -1 {
+		t.Errorf("ListPointsBetween() = %+v, want U2 last with -1", records)
+	}
+
+	if records, err := repo.ListPointsBetween(ctx, may, june, 1); err != nil || len(records) != 1 || records[0].UserID != "sake" {
+		t.Errorf("ListPointsBetween(limit 1) = %+v, %v, want sake", records, err)
+	}
+
+	// Events at the ends of a window not aligned to hours are counted
+	records, err = repo.ListPointsBetween(ctx, day.Add(11*time.Hour+45*time.Minute), day.Add(24*time.Hour+5*time.Minute), 0)
+	if err != nil {
+		t.Fatalf("ListPointsBetween() error = %v", err)
+	}
+	if len(records) != 2 || records[0].UserID != "U2" || records[0].Points != 2 || records[1].UserID != "U1" || records[1].Points != 1 {
+		t.Errorf("ListPointsBetween() not aligned to hours = %+v, want U2 with 2 and U1 with 1", records)
+	}
+}
+
+func TestDynamoDBAddPointsWithEvent(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+	events := []PointEvent{
+		{Target: "U2", Timestamp: start, Giver: "U1", Delta: 2, Reason: "for the review"},
+		{Target: "U2", Timestamp: start.Add(time.Minute), Giver: "U3", Delta: -1},
+	}
+	wantChanges := []PointChange{{Delta: 2, Points: 2}, {Delta: -1, Points: 1}}
+	for i, event := range events {
+		// Adding an event twice changes the points once
+		for range 2 {
+			change, err := repo.AddPointsWithEvent(ctx, event, true)
+			if err != nil {
+				t.Fatalf("AddPointsWithEvent() error = %v", err)
+			}
+			if change != wantChanges[i] {
+				t.Errorf("AddPointsWithEvent() = %+v, want %+v", change, wantChanges[i])
+			}
+		}
+	}
+
+	record, err := repo.GetUserPoints(ctx, "U2")
+	if err != nil {
+		t.Fatalf("GetUserPoints() error = %v", err)
+	}
+	if record.Points != 1 || !record.IsUser {
+		t.Errorf("GetUserPoints() = %+v, want 1 point for a user", record)
+	}
+	got, err := repo.ListPointEvents(ctx, "U2", start)
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Delta != 2 || got[0].Reason != "for the review" || got[1].Delta != -1 {
+		t.Errorf("ListPointEvents() = %+v, want both events", got)
+	}
+	if points, err := repo.GetPointsBetween(ctx, "U2", start, start.Add(time.Hour)); err != nil || points != 1 {
+		t.Errorf("GetPointsBetween() = %d, %v, want 1", points, err)
+	}
+}
+
+func TestDynamoDBSeasons(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	spring := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	summer := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := CurrentSeason(ctx, repo); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("CurrentSeason() error = %v, want ErrNotFound", err)
+	}
+	if _, err := StartSeason(ctx, repo, "2025-Q2", spring); err != nil {
+		t.Fatalf("StartSeason() error = %v", err)
+	}
+	if _, err := StartSeason(ctx, repo, "other", spring); !errors.Is(err, ErrSeasonInProgress) {
+		t.Errorf("StartSeason() error = %v, want ErrSeasonInProgress", err)
+	}
+
+	if err := repo.AddPoints(ctx, "U1", 5, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	events := []PointEvent{
+		{Target: "U1", Timestamp: spring.Add(-time.Hour), Giver: "U2", Delta: 2},
+		{Target: "U1", Timestamp: spring.AddDate(0, 1, 0), Giver: "U2", Delta: 1},
+		{Target: "sake", Timestamp: spring.AddDate(0, 2, 0), Giver: "U1", Delta: 2},
+		{Target: "U1", Timestamp: summer.Add(time.Hour), Giver: "U2", Delta: 2},
+	}
+	for _, event := range events {
+		if err := repo.AddPointEvent(ctx, event); err != nil {
+			t.Fatalf("AddPointEvent() error = %v", err)
+		}
+	}
+
+	season, standings, err := EndSeason(ctx, repo, summer)
+	if err != nil {
+		t.Fatalf("EndSeason() error = %v", err)
+	}
+	if season.Name != "2025-Q2" || !season.End.Equal(summer) {
+		t.Errorf("EndSeason() season = %+v, want 2025-Q2 ending at %v", season, summer)
+	}
+	want := []UserPoints{{UserID: "sake", Points: 2}, {UserID: "U1", Points: 1, IsUser: true}}
+	if len(standings) != len(want) {
+		t.Fatalf("EndSeason() standings = %+v, want %+v", standings, want)
+	}
+	if _, _, err := EndSeason(ctx, repo, summer); !errors.Is(err, ErrNotFound) {
+		t.Errorf("EndSeason() without a season error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := StartSeason(ctx, repo, "2025-Q2", summer); !errors.Is(err, ErrSeasonExists) {
+		t.Errorf("StartSeason() error = %v, want ErrSeasonExists", err)
+	}
+	if _, err := StartSeason(ctx, repo, "2025-Q3", summer); err != nil {
+		t.Fatalf("StartSeason() error = %v", err)
+	}
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		t.Fatalf("ListSeasons() error = %v", err)
+	}
+	if len(seasons) != 2 || seasons[0].Name != "2025-Q2" || !seasons[0].Start.Equal(spring) ||
+		seasons[1].Name != "2025-Q3" || !seasons[1].End.IsZero() {
+		t.Errorf("ListSeasons() = %+v, want 2025-Q2 then 2025-Q3 in progress", seasons)
+	}
+
+	got, err := repo.ListSeasonStandings(ctx, "2025-Q2", 0)
+	if err != nil {
+		t.Fatalf("ListSeasonStandings() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ListSeasonStandings() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].UserID != want[i].UserID || got[i].Points != want[i].Points || got[i].IsUser != want[i].IsUser {
+			t.Errorf("ListSeasonStandings()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got, err := repo.ListSeasonStandings(ctx, "2025-Q2", 1); err != nil || len(got) != 1 {
+		t.Errorf("ListSeasonStandings(limit 1) = %+v, %v, want 1 record", got, err)
+	}
+	if got, err := repo.ListSeasonStandings(ctx, "unknown", 0); err != nil || len(got) != 0 {
+		t.Errorf("ListSeasonStandings(unknown) = %+v, %v, want none", got, err)
+	}
+}
+
+func TestDynamoDBBadges(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	first := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+	awards := []struct {
+		badge   UserBadge
+		wantNew bool
+	}{
+		{UserBadge{UserID: "U1", Badge: "hundred-club", AwardedAt: first.Add(time.Hour)}, true},
+		{UserBadge{UserID: "U1", Badge: "first-plus", AwardedAt: first}, true},
+		{UserBadge{UserID: "U2", Badge: "first-plus", AwardedAt: first}, true},
+		// Awarding a badge again keeps the first award
+		{UserBadge{UserID: "U1", Badge: "first-plus", AwardedAt: first.Add(2 * time.Hour)}, false},
+	}
+	for _, award := range awards {
+		added, err := repo.AddBadge(ctx, award.badge)
+		if err != nil {
+			t.Fatalf("AddBadge() error = %v", err)
+		}
+		if added != award.wantNew {
+			t.Errorf("AddBadge(%+v) = %v, want %v", award.badge, added, award.wantNew)
+		}
+	}
+
+	badges, err := repo.ListBadges(ctx, "U1")
 	if err != nil {
-		t.Errorf("Table was not created: %v", err)
+		t.Fatalf("ListBadges() error = %v", err)
+	}
+	if len(badges) != 2 || badges[0].Badge != "first-plus" || !badges[0].AwardedAt.Equal(first) || badges[1].Badge != "hundred-club" {
+		t.Errorf("ListBadges() = %+v, want first-plus then hundred-club", badges)
+	}
+	if badges, err := repo.ListBadges(ctx, "U3"); err != nil || len(badges) != 0 {
+		t.Errorf("ListBadges(U3) = %+v, %v, want none", badges, err)
+	}
+}
+
+func TestDynamoDBGivingStreaks(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	streak, err := repo.GetGivingStreak(ctx, "U1")
+	if err != nil {
+		t.Fatalf("GetGivingStreak() error = %v", err)
+	}
+	if *streak != (GivingStreak{UserID: "U1"}) {
+		t.Errorf("GetGivingStreak() = %+v, want a zero streak", streak)
+	}
+
+	for _, streak := range []GivingStreak{
+		{UserID: "U2", Current: 1, Longest: 4, LastDay: "2025-05-15"},
+		{UserID: "U1", Current: 2, Longest: 2, LastDay: "2025-05-15"},
+		{UserID: "U1", Current: 3, Longest: 3, LastDay: "2025-05-16"},
+	} {
+		if err := repo.PutGivingStreak(ctx, streak); err != nil {
+			t.Fatalf("PutGivingStreak() error = %v", err)
+		}
+	}
+
+	want := GivingStreak{UserID: "U1", Current: 3, Longest: 3, LastDay: "2025-05-16"}
+	if streak, err := repo.GetGivingStreak(ctx, "U1"); err != nil || *streak != want {
+		t.Errorf("GetGivingStreak() = %+v, %v, want %+v", streak, err, want)
+	}
+	streaks, err := repo.ListGivingStreaks(ctx)
+	if err != nil {
+		t.Fatalf("ListGivingStreaks() error = %v", err)
+	}
+	if len(streaks) != 2 || streaks[0] != want || streaks[1].UserID != "U2" || streaks[1].Longest != 4 {
+		t.Errorf("ListGivingStreaks() = %+v, want U1 then U2", streaks)
+	}
+}
+
+func TestDynamoDBGiverStats(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	stats, err := repo.GetGiverStats(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetGiverStats() error = %v", err)
+	}
+	if stats.UserID != "user1" || stats.Given != 0 || stats.Recipients != 0 || !stats.LastGiven.IsZero() {
+		t.Errorf("GetGiverStats() = %+v, want zero counters", stats)
+	}
+
+	for _, recipient := range []string{"user2", "sake", "user2"} {
+		if err := repo.AddGivenPoints(ctx, "user1", recipient, 1); err != nil {
+			t.Fatalf("AddGivenPoints() error = %v", err)
+		}
+	}
+	if err := repo.AddGivenPoints(ctx, "user2", "user1", 1); err != nil {
+		t.Fatalf("AddGivenPoints() error = %v", err)
+	}
+
+	stats, err = repo.GetGiverStats(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetGiverStats() error = %v", err)
+	}
+	if stats.Given != 3 || stats.Recipients != 2 || time.Since(stats.LastGiven) > time.Minute {
+		t.Errorf("GetGiverStats() = %+v, want 3 points given to 2 recipients just now", stats)
+	}
+
+	givers, err := repo.ListGivers(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListGivers() error = %v", err)
+	}
+	if len(givers) != 1 || givers[0].UserID != "user1" || givers[0].Recipients != 2 {
+		t.Errorf("ListGivers(1) = %+v, want user1 first", givers)
+	}
+	if givers, err := repo.ListGivers(ctx, 0); err != nil || len(givers) != 2 {
+		t.Errorf("ListGivers(0) = %+v, %v, want every giver", givers, err)
+	}
+
+	given, err := repo.ListGivenPoints(ctx, "user1", 0)
+	if err != nil {
+		t.Fatalf("ListGivenPoints() error = %v", err)
+	}
+	want := []UserPoints{{UserID: "user2", Points: 2}, {UserID: "sake", Points: 1}}
+	if !slices.Equal(given, want) {
+		t.Errorf("ListGivenPoints(0) = %+v, want %+v", given, want)
+	}
+	if given, err := repo.ListGivenPoints(ctx, "user1", 1); err != nil || len(given) != 1 || given[0].UserID != "user2" {
+		t.Errorf("ListGivenPoints(1) = %+v, %v, want user2 only", given, err)
+	}
+}
+
+func TestWindowBuckets(t *testing.T) {
+	day := time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	tests := []struct {
+		name        string
+		from, to    time.Time
+		wantBuckets []string
+		wantEdges   int
+	}{
+		{"empty", day, day, nil, 0},
+		{"hours", day.Add(22 * time.Hour), day.Add(24 * time.Hour), []string{"hour#2025-05-31T22", "hour#2025-05-31T23"}, 0},
+		{"day and month", day, time.Date(2025, 7, 1, 1, 0, 0, 0, time.UTC), []string{"day#2025-05-31", "month#2025-06", "hour#2025-07-01T00"}, 0},
+		{"day in Tokyo", time.Date(2025, 6, 1, 0, 0, 0, 0, tokyo), time.Date(2025, 6, 2, 0, 0, 0, 0, tokyo), []string{"hour#2025-05-31T15", "hour#2025-05-31T16", "hour#2025-05-31T17", "hour#2025-05-31T18", "hour#2025-05-31T19", "hour#2025-05-31T20", "hour#2025-05-31T21", "hour#2025-05-31T22", "hour#2025-05-31T23", "hour#2025-06-01T00", "hour#2025-06-01T01", "hour#2025-06-01T02", "hour#2025-06-01T03", "hour#2025-06-01T04", "hour#2025-06-01T05", "hour#2025-06-01T06", "hour#2025-06-01T07", "hour#2025-06-01T08", "hour#2025-06-01T09", "hour#2025-06-01T10", "hour#2025-06-01T11", "hour#2025-06-01T12", "hour#2025-06-01T13", "hour#2025-06-01T14"}, 0},
+		{"not aligned", day.Add(30 * time.Minute), day.Add(2*time.Hour + 15*time.Minute), []string{"hour#2025-05-31T01"}, 2},
+		{"within an hour", day.Add(10 * time.Minute), day.Add(20 * time.Minute), nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets, edges := windowBuckets(tt.from, tt.to)
+			if !slices.Equal(buckets, tt.wantBuckets) {
+				t.Errorf("windowBuckets() buckets = %v, want %v", buckets, tt.wantBuckets)
+			}
+			if len(edges) != tt.wantEdges {
+				t.Errorf("windowBuckets() edges = %v, want %d", edges, tt.wantEdges)
+			}
+		})
+	}
+}
+
+func TestDynamoDBAddPointsFloored(t *testing.T) {
+	repo, cleanup := setupTestDynamoDBRepository(t)
+	defer cleanup()
+
+	ctx := withFloorAtZero(context.Background())
+	if err := repo.AddPoints(ctx, "user1", 2, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if err := repo.AddPoints(ctx, "user1", -5, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "user1"); points != 0 {
+		t.Errorf("GetPoints() after going below zero = %d, want 0", points)
+	}
+
+	event := PointEvent{Target: "user1", Timestamp: time.Now(), Giver: "user2", Delta: -1}
+	if change, err := repo.AddPointsWithEvent(ctx, event, true); err != nil || change != (PointChange{Points: 0}) {
+		t.Fatalf("AddPointsWithEvent() = %+v, %v, want no change", change, err)
+	}
+	events, err := repo.ListPointEvents(ctx, "user1", time.Time{})
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Delta != 0 {
+		t.Errorf("ListPointEvents() = %+v, want one event without points", events)
 	}
 }
@@ -7,14 +7,24 @@ import (
 	"plusplusbot/infra/config"
 )
 
-// NewRepository creates a new repository based on the configuration
+// NewRepository creates a new repository based on the configuration, enforcing its point policy
 func NewRepository(cfg *config.Config, logger *slog.Logger) (UserPointsRepository, error) {
+	var repo UserPointsRepository
+	var err error
 	switch cfg.RepositoryType {
 	case config.SQLiteRepository:
-		return NewSQLiteRepository(cfg.SQLiteDBPath, logger)
+		repo, err = NewSQLiteRepository(cfg.SQLiteDBPath, logger)
 	case config.DynamoDBRepository:
-		return NewDynamoDBRepository(cfg.DynamoDBTableName, cfg.DynamoDBLocal, logger)
+		repo, err = NewDynamoDBRepository(cfg.DynamoDBTableName, cfg.DynamoDBLocal, cfg.DynamoDBCreateTables, logger)
 	default:
 		return nil, fmt.Errorf("unsupported repository type: %s", cfg.RepositoryType)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPolicyRepository(repo, Policy{
+		AllowMinus:  !cfg.DisableMinus,
+		FloorAtZero: cfg.FloorAtZero,
+	}), nil
 }
@@ -64,7 +64,11 @@ func TestNewRepository(t *testing.T) {
 			}
 
 			if !tt.wantErr {
-				gotType := fmt.Sprintf("%T", repo)
+				policyRepo, ok := repo.(*PolicyRepository)
+				if !ok {
+					t.Fatalf("NewRepository() = %T, want the repository wrapped in a *repository.PolicyRepository", repo)
+				}
+				gotType := fmt.Sprintf("%T", policyRepo.UserPointsRepository)
 				if gotType != tt.wantType {
 					t.Errorf("NewRepository() = %v, want %v", gotType, tt.wantType)
 				}
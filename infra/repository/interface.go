@@ -2,8 +2,99 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 )
 
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// UserPoints represents the points of a target (a user or an emoji)
+type UserPoints struct {
+	UserID       string    `dynamo:"user_id,hash"`
+	Points       int       `dynamo:"points"`
+	IsUser       bool      `dynamo:"is_user"`
+	LastModified time.Time `dynamo:"last_modified"`
+}
+
+// UserSettings holds the preferences of a user
+type UserSettings struct {
+	UserID string `dynamo:"user_id,hash"`
+	// Notify enables direct messages when the user receives points
+	Notify bool `dynamo:"notify"`
+}
+
+// ChannelSettings holds the settings of a channel. Unset fields fall back to the workspace configuration.
+type ChannelSettings struct {
+	ChannelID string `dynamo:"channel_id,hash"`
+	// Enabled turns the bot on or off in the channel
+	Enabled *bool `dynamo:"enabled"`
+	// AllowMinus allows or forbids taking points away with --
+	AllowMinus *bool `dynamo:"allow_minus"`
+	// ReplyMode is how the bot responds to point changes in the channel
+	ReplyMode string `dynamo:"reply_mode"`
+	// Locale is the locale of replies in the channel
+	Locale string `dynamo:"locale"`
+}
+
+// PointEvent records a point change made by a user, keeping the history of who gave points to whom
+type PointEvent struct {
+	Target    string    `dynamo:"target,hash"`
+	Timestamp time.Time `dynamo:"timestamp,range"`
+	Giver     string    `dynamo:"giver"`
+	Channel   string    `dynamo:"channel"`
+	// Delta is the change applied to the points of the target
+	Delta int `dynamo:"delta"`
+	// Dampened is set when the points were not added because the giver and the target trade points too often
+	Dampened bool `dynamo:"dampened"`
+	// Reason is the text given after the operation, if any
+	Reason string `dynamo:"reason,omitempty"`
+}
+
+// PointChange is a point change as stored: the delta applied, which can be smaller than requested when totals
+// are floored at zero, and the total of the target after it
+type PointChange struct {
+	Delta  int
+	Points int
+}
+
+// GiverStats holds the counters of the points a user has given
+type GiverStats struct {
+	UserID string
+	// Given is the total of the points given
+	Given int
+	// Recipients is the number of different targets points were given to
+	Recipients int
+	// LastGiven is when points were last given, or zero if never
+	LastGiven time.Time
+}
+
+// Season is a period points are ranked in apart from the all-time totals
+type Season struct {
+	Name  string    `dynamo:"name,hash"`
+	Start time.Time `dynamo:"start"`
+	// End is when the season ended, or zero for the season in progress
+	End time.Time `dynamo:"end,omitempty"`
+}
+
+// UserBadge is a badge awarded to a user
+type UserBadge struct {
+	UserID    string    `dynamo:"user_id,hash"`
+	Badge     string    `dynamo:"badge,range"`
+	AwardedAt time.Time `dynamo:"awarded_at"`
+}
+
+// GivingStreak is the run of consecutive days a user has given points on
+type GivingStreak struct {
+	UserID string `dynamo:"user_id,hash"`
+	// Current is the number of days in the run ending on LastDay
+	Current int `dynamo:"current"`
+	// Longest is the longest run so far
+	Longest int `dynamo:"longest"`
+	// LastDay is the last day the user gave points on, like "2025-05-16", in the time zone of the bot
+	LastDay string `dynamo:"last_day"`
+}
+
 // UserPointsRepository defines the interface for user points storage operations
 type UserPointsRepository interface {
 	// AddPoints adds points to a user
@@ -12,6 +103,111 @@ type UserPointsRepository interface {
 	// GetPoints gets the current points for a user
 	GetPoints(ctx context.Context, userID string) (int, error)
 
+	// GetUserPoints gets the points record for a user, or ErrNotFound if there is none
+	GetUserPoints(ctx context.Context, userID string) (*UserPoints, error)
+
+	// SetPoints sets the points of a user to the given value
+	SetPoints(ctx context.Context, userID string, points int, isUser bool) error
+
+	// DeletePoints deletes the points record for a user
+	DeletePoints(ctx context.Context, userID string) error
+
+	// ListPoints lists points records ordered by points, highest first.
+	// A limit of zero or less lists all records.
+	ListPoints(ctx context.Context, limit int) ([]UserPoints, error)
+
+	// GetRank gets the 1-based leaderboard position of a user; users with equal points share a rank.
+	// It returns ErrNotFound if the user has no points record.
+	GetRank(ctx context.Context, userID string) (int, error)
+
+	// ScanPoints calls fn for every points record without loading them all into memory.
+	// Scanning stops at the first error returned by fn.
+	ScanPoints(ctx context.Context, fn func(UserPoints) error) error
+
+	// PutUserPoints stores a points record as is, including its last modified time
+	PutUserPoints(ctx context.Context, userPoints UserPoints) error
+
+	// MovePoints stores the points record to and deletes the points record of from in one transaction,
+	// so that points are never left in both or in neither
+	MovePoints(ctx context.Context, from string, to UserPoints) error
+
+	// GetUserSettings gets the preferences of a user, or the defaults if none are stored
+	GetUserSettings(ctx context.Context, userID string) (*UserSettings, error)
+
+	// PutUserSettings stores the preferences of a user
+	PutUserSettings(ctx context.Context, settings UserSettings) error
+
+	// GetChannelSettings gets the settings of a channel, with no fields set if none are stored
+	GetChannelSettings(ctx context.Context, channelID string) (*ChannelSettings, error)
+
+	// PutChannelSettings stores the settings of a channel
+	PutChannelSettings(ctx context.Context, settings ChannelSettings) error
+
+	// AddPointEvent records a point change in the history. Adding the same event twice stores it once.
+	AddPointEvent(ctx context.Context, event PointEvent) error
+
+	// AddPointsWithEvent adds the delta of an event to the points of its target and records the event in the
+	// history in one transaction, and returns the change stored. Adding the same event twice changes the points
+	// once, and returns the change stored the first time with the current total.
+	AddPointsWithEvent(ctx context.Context, event PointEvent, isUser bool) (PointChange, error)
+
+	// ListPointEvents lists the point changes received by a target since the given time, oldest first.
+	// An empty target lists the point changes of every target.
+	ListPointEvents(ctx context.Context, target string, since time.Time) ([]PointEvent, error)
+
+	// ScanPointEvents calls fn for every point change without loading them all into memory.
+	// Scanning stops at the first error returned by fn.
+	ScanPointEvents(ctx context.Context, fn func(PointEvent) error) error
+
+	// GetPointsBetween gets the points a target received from from to to, excluding to, according to the history
+	GetPointsBetween(ctx context.Context, target string, from, to time.Time) (int, error)
+
+	// ListPointsBetween lists the points received from from to to, excluding to, ordered by points, highest first.
+	// Targets whose points did not change in the window are left out. A limit of zero or less lists all targets.
+	ListPointsBetween(ctx context.Context, from, to time.Time, limit int) ([]UserPoints, error)
+
+	// PutSeason stores a season
+	PutSeason(ctx context.Context, season Season) error
+
+	// ListSeasons lists the seasons ordered by their start, oldest first
+	ListSeasons(ctx context.Context) ([]Season, error)
+
+	// PutSeasonStandings stores the final points of the targets in a season
+	PutSeasonStandings(ctx context.Context, season string, standings []UserPoints) error
+
+	// ListSeasonStandings lists the final points of the targets in a season ordered by points, highest first.
+	// A limit of zero or less lists all targets.
+	ListSeasonStandings(ctx context.Context, season string, limit int) ([]UserPoints, error)
+
+	// AddBadge awards a badge to a user and reports whether it is new; awarding it again keeps the first award
+	AddBadge(ctx context.Context, badge UserBadge) (bool, error)
+
+	// ListBadges lists the badges awarded to a user ordered by when they were awarded, oldest first
+	ListBadges(ctx context.Context, userID string) ([]UserBadge, error)
+
+	// GetGivingStreak gets the giving streak of a user, all zero if the user has never given points
+	GetGivingStreak(ctx context.Context, userID string) (*GivingStreak, error)
+
+	// PutGivingStreak stores the giving streak of a user
+	PutGivingStreak(ctx context.Context, streak GivingStreak) error
+
+	// ListGivingStreaks lists the giving streaks of every user ordered by user ID
+	ListGivingStreaks(ctx context.Context) ([]GivingStreak, error)
+
+	// AddGivenPoints adds points given by a user to a target to the counters of the giver
+	AddGivenPoints(ctx context.Context, giver, recipient string, points int) error
+
+	// GetGiverStats gets the counters of a giver, all zero if the user has never given points
+	GetGiverStats(ctx context.Context, giver string) (*GiverStats, error)
+
+	// ListGivers lists the counters of givers ordered by the points given, highest first.
+	// A limit of zero or less lists all givers.
+	ListGivers(ctx context.Context, limit int) ([]GiverStats, error)
+
+	// ListGivenPoints lists the targets a giver has given points to with the points given to each, highest first.
+	// IsUser is not known and left false. A limit of zero or less lists all targets.
+	ListGivenPoints(ctx context.Context, giver string, limit int) ([]UserPoints, error)
+
 	// Close closes the repository connection
 	Close() error
 }
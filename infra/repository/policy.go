@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrMinusDisabled is returned when points are taken away while negative points are disabled
+var ErrMinusDisabled = errors.New("negative points are disabled")
+
+// Policy restricts the point changes a repository accepts
+type Policy struct {
+	// AllowMinus allows taking points away; channel settings can override it
+	AllowMinus bool
+	// FloorAtZero keeps totals from going below zero
+	FloorAtZero bool
+}
+
+// DefaultPolicy accepts every point change
+var DefaultPolicy = Policy{AllowMinus: true}
+
+// channelKey is the context key of the channel a point change is made in
+type channelKey struct{}
+
+// WithChannel returns a context for point changes made in a channel, so that its settings apply
+func WithChannel(ctx context.Context, channelID string) context.Context {
+	return context.WithValue(ctx, channelKey{}, channelID)
+}
+
+// ChannelFromContext returns the channel set with WithChannel, or an empty string
+func ChannelFromContext(ctx context.Context) string {
+	channelID, _ := ctx.Value(channelKey{}).(string)
+	return channelID
+}
+
+// PolicyRepository enforces a Policy on top of another repository, so that every entry point
+// (messages, admin commands, imports) respects it
+type PolicyRepository struct {
+	UserPointsRepository
+	policy Policy
+}
+
+// NewPolicyRepository creates a repository enforcing policy on repo
+func NewPolicyRepository(repo UserPointsRepository, policy Policy) *PolicyRepository {
+	return &PolicyRepository{
+		UserPointsRepository: repo,
+		policy:               policy,
+	}
+}
+
+// floorKey is the context key set when a point change must not take a total below zero
+type floorKey struct{}
+
+// withFloorAtZero returns a context for point changes that must not take a total below zero.
+// Repositories apply the floor in the same update as the change, so that concurrent changes can't get around it.
+func withFloorAtZero(ctx context.Context) context.Context {
+	return context.WithValue(ctx, floorKey{}, true)
+}
+
+// floorAtZero reports whether point changes made with ctx must not take a total below zero
+func floorAtZero(ctx context.Context) bool {
+	floored, _ := ctx.Value(floorKey{}).(bool)
+	return floored
+}
+
+// flooredPoints returns the points that can be added to a total of current without taking it below zero.
+// A total already below zero, such as one imported before the policy was enabled, stays where it is.
+func flooredPoints(current, points int) int {
+	return max(current+points, min(current, 0)) - current
+}
+
+// minusAllowed reports whether points can be taken away, taking the settings of the channel in ctx into account
+func (r *PolicyRepository) minusAllowed(ctx context.Context) (bool, error) {
+	if channelID := ChannelFromContext(ctx); channelID != "" {
+		settings, err := r.GetChannelSettings(ctx, channelID)
+		if err != nil {
+			return false, err
+		}
+		if settings.AllowMinus != nil {
+			return *settings.AllowMinus, nil
+		}
+	}
+	return r.policy.AllowMinus, nil
+}
+
+// AddPoints adds points to a user. Negative points fail with ErrMinusDisabled when they are not allowed,
+// and are reduced so that the total does not go below zero when totals are floored.
+func (r *PolicyRepository) AddPoints(ctx context.Context, userID string, points int, isUser bool) error {
+	ctx, err := r.pointChangeContext(ctx, points)
+	if err != nil {
+		return err
+	}
+	return r.UserPointsRepository.AddPoints(ctx, userID, points, isUser)
+}
+
+// AddPointsWithEvent adds the delta of an event to the points of its target and records the event, applying
+// the policy to the delta like AddPoints
+func (r *PolicyRepository) AddPointsWithEvent(ctx context.Context, event PointEvent, isUser bool) (PointChange, error) {
+	ctx, err := r.pointChangeContext(ctx, event.Delta)
+	if err != nil {
+		return PointChange{}, err
+	}
+	return r.UserPointsRepository.AddPointsWithEvent(ctx, event, isUser)
+}
+
+// pointChangeContext checks that points can be added, and returns the context to add them with,
+// which floors the total at zero when the policy does
+func (r *PolicyRepository) pointChangeContext(ctx context.Context, points int) (context.Context, error) {
+	if points >= 0 {
+		return ctx, nil
+	}
+	allowed, err := r.minusAllowed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrMinusDisabled
+	}
+	if r.policy.FloorAtZero {
+		ctx = withFloorAtZero(ctx)
+	}
+	return ctx, nil
+}
+
+// allowedTotal checks that a total can be stored, failing with ErrMinusDisabled for a negative total when
+// negative points are not allowed, and returns it raised to zero when totals are floored
+func (r *PolicyRepository) allowedTotal(ctx context.Context, points int) (int, error) {
+	if points >= 0 {
+		return points, nil
+	}
+	allowed, err := r.minusAllowed(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !allowed {
+		return 0, ErrMinusDisabled
+	}
+	if r.policy.FloorAtZero {
+		return 0, nil
+	}
+	return points, nil
+}
+
+// SetPoints sets the points of a user, applying the policy to the total like allowedTotal
+func (r *PolicyRepository) SetPoints(ctx context.Context, userID string, points int, isUser bool) error {
+	points, err := r.allowedTotal(ctx, points)
+	if err != nil {
+		return err
+	}
+	return r.UserPointsRepository.SetPoints(ctx, userID, points, isUser)
+}
+
+// PutUserPoints stores a points record, applying the policy to the total like allowedTotal
+func (r *PolicyRepository) PutUserPoints(ctx context.Context, userPoints UserPoints) error {
+	points, err := r.allowedTotal(ctx, userPoints.Points)
+	if err != nil {
+		return err
+	}
+	userPoints.Points = points
+	return r.UserPointsRepository.PutUserPoints(ctx, userPoints)
+}
+
+// MovePoints moves a points record, applying the policy to the total like allowedTotal
+func (r *PolicyRepository) MovePoints(ctx context.Context, from string, to UserPoints) error {
+	points, err := r.allowedTotal(ctx, to.Points)
+	if err != nil {
+		return err
+	}
+	to.Points = points
+	return r.UserPointsRepository.MovePoints(ctx, from, to)
+}
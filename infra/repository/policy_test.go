@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPolicyRepositoryMinus(t *testing.T) {
+	sqlite, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	allowed, forbidden := true, false
+	if err := sqlite.PutChannelSettings(ctx, ChannelSettings{ChannelID: "CALLOW", AllowMinus: &allowed}); err != nil {
+		t.Fatalf("PutChannelSettings() error = %v", err)
+	}
+	if err := sqlite.PutChannelSettings(ctx, ChannelSettings{ChannelID: "CDENY", AllowMinus: &forbidden}); err != nil {
+		t.Fatalf("PutChannelSettings() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		allowMinus bool
+		channel    string
+		wantErr    error
+	}{
+		{"allowed", true, "", nil},
+		{"disabled", false, "", ErrMinusDisabled},
+		{"disabled in workspace, allowed in channel", false, "CALLOW", nil},
+		{"allowed in workspace, disabled in channel", true, "CDENY", ErrMinusDisabled},
+		{"channel without settings", false, "COTHER", ErrMinusDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPolicyRepository(sqlite, Policy{AllowMinus: tt.allowMinus})
+			ctx := ctx
+			if tt.channel != "" {
+				ctx = WithChannel(ctx, tt.channel)
+			}
+
+			err := repo.AddPoints(ctx, "user1", -1, true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddPoints() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := repo.AddPoints(ctx, "user1", 1, true); err != nil {
+				t.Errorf("AddPoints() of positive points error = %v", err)
+			}
+
+			// Negative totals are refused on every write path
+			if err := repo.SetPoints(ctx, "user2", -1, true); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SetPoints() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := repo.PutUserPoints(ctx, UserPoints{UserID: "user2", Points: -1}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PutUserPoints() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := repo.MovePoints(ctx, "user3", UserPoints{UserID: "user2", Points: -1}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("MovePoints() error = %v, want %v", err, tt.wantErr)
+			}
+			event := PointEvent{Target: "user2", Timestamp: time.Now(), Giver: "user1", Delta: -1}
+			if _, err := repo.AddPointsWithEvent(ctx, event, true); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddPointsWithEvent() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyRepositoryFloorAtZero(t *testing.T) {
+	sqlite, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewPolicyRepository(sqlite, Policy{AllowMinus: true, FloorAtZero: true})
+
+	if err := repo.AddPoints(ctx, "user1", 2, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if err := repo.AddPoints(ctx, "user1", -5, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "user1"); points != 0 {
+		t.Errorf("GetPoints() after going below zero = %d, want 0", points)
+	}
+
+	if err := repo.SetPoints(ctx, "user2", -3, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "user2"); points != 0 {
+		t.Errorf("GetPoints() after SetPoints(-3) = %d, want 0", points)
+	}
+
+	if err := repo.PutUserPoints(ctx, UserPoints{UserID: "user3", Points: -7}); err != nil {
+		t.Fatalf("PutUserPoints() error = %v", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "user3"); points != 0 {
+		t.Errorf("GetPoints() after PutUserPoints(-7) = %d, want 0", points)
+	}
+
+	// A total that is already negative is not raised by taking points away
+	if err := sqlite.SetPoints(ctx, "user4", -5, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	if err := repo.AddPoints(ctx, "user4", -1, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "user4"); points != -5 {
+		t.Errorf("GetPoints() after -1 from -5 = %d, want -5", points)
+	}
+	if err := repo.AddPoints(ctx, "user4", 2, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "user4"); points != -3 {
+		t.Errorf("GetPoints() after +2 from -5 = %d, want -3", points)
+	}
+	// The delta recorded with the points is the one applied
+	event := PointEvent{Target: "user5", Timestamp: time.Now(), Giver: "user1", Delta: -3}
+	if change, err := repo.AddPointsWithEvent(ctx, event, true); err != nil || change != (PointChange{Points: 0}) {
+		t.Fatalf("AddPointsWithEvent() = %+v, %v, want no change", change, err)
+	}
+	if points, _ := repo.GetPoints(ctx, "user5"); points != 0 {
+		t.Errorf("GetPoints() after AddPointsWithEvent(-3) = %d, want 0", points)
+	}
+	if events, err := repo.ListPointEvents(ctx, "user5", time.Time{}); err != nil || len(events) != 1 || events[0].Delta != 0 {
+		t.Errorf("ListPointEvents() = %+v, %v, want one event without points", events, err)
+	}
+}
+
+func TestPolicyRepositoryFloorAtZeroConcurrent(t *testing.T) {
+	sqlite, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewPolicyRepository(sqlite, Policy{AllowMinus: true, FloorAtZero: true})
+	if err := repo.AddPoints(ctx, "user1", 3, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+
+	// Concurrent changes can't take the total below zero, and the history records what was applied
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := repo.AddPoints(ctx, "user1", -1, true); err != nil {
+				t.Errorf("AddPoints() error = %v", err)
+			}
+			event := PointEvent{Target: "user1", Timestamp: start.Add(time.Duration(i) * time.Millisecond), Giver: "user2", Delta: -1}
+			if change, err := repo.AddPointsWithEvent(ctx, event, true); err != nil || change.Points < 0 {
+				t.Errorf("AddPointsWithEvent() = %+v, %v, want a total of at least zero", change, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if points, _ := repo.GetPoints(ctx, "user1"); points != 0 {
+		t.Errorf("GetPoints() after concurrent changes = %d, want 0", points)
+	}
+	events, err := repo.ListPointEvents(ctx, "user1", time.Time{})
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	applied := 0
+	for _, event := range events {
+		applied += event.Delta
+	}
+	if len(events) != 10 || applied < -3 {
+		t.Errorf("ListPointEvents() = %+v, want 10 events applying at most -3", events)
+	}
+}
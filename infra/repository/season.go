@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSeasonInProgress is returned when a season is started while another one is in progress
+var ErrSeasonInProgress = errors.New("a season is already in progress")
+
+// ErrSeasonExists is returned when a season is started with the name of an earlier one
+var ErrSeasonExists = errors.New("season already exists")
+
+// FindSeason gets a season by name, or ErrNotFound if there is none
+func FindSeason(ctx context.Context, repo UserPointsRepository, name string) (*Season, error) {
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, season := range seasons {
+		if season.Name == name {
+			return &season, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CurrentSeason gets the season in progress, or ErrNotFound if there is none
+func CurrentSeason(ctx context.Context, repo UserPointsRepository) (*Season, error) {
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, season := range seasons {
+		if season.End.IsZero() {
+			return &season, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// StartSeason starts a season at start. Only one season can be in progress at a time.
+func StartSeason(ctx context.Context, repo UserPointsRepository, name string, start time.Time) (*Season, error) {
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, season := range seasons {
+		if season.End.IsZero() {
+			return nil, fmt.Errorf("%s: %w", season.Name, ErrSeasonInProgress)
+		}
+		if season.Name == name {
+			return nil, fmt.Errorf("%s: %w", name, ErrSeasonExists)
+		}
+	}
+
+	season := Season{Name: name, Start: start}
+	if err := repo.PutSeason(ctx, season); err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+// EndSeason ends the season in progress at end, archiving the points every target received during it,
+// and returns the season with its final standings. The all-time totals are left as they are.
+func EndSeason(ctx context.Context, repo UserPointsRepository, end time.Time) (*Season, []UserPoints, error) {
+	season, err := CurrentSeason(ctx, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	standings, err := repo.ListPointsBetween(ctx, season.Start, end, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := repo.PutSeasonStandings(ctx, season.Name, standings); err != nil {
+		return nil, nil, err
+	}
+
+	season.End = end
+	if err := repo.PutSeason(ctx, *season); err != nil {
+		return nil, nil, err
+	}
+	return season, standings, nil
+}
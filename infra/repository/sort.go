@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"sort"
+)
+
+// sortUserPoints sorts records by points, highest first, breaking ties by user ID
+func sortUserPoints(records []UserPoints) {
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Points != records[j].Points {
+			return records[i].Points > records[j].Points
+		}
+		return records[i].UserID < records[j].UserID
+	})
+}
+
+// sortPointEvents sorts events by time, oldest first, breaking ties by target
+func sortPointEvents(events []PointEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		return events[i].Target < events[j].Target
+	})
+}
+
+// sortGiverStats sorts giver counters by the points given, highest first, breaking ties by user ID
+func sortGiverStats(stats []GiverStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Given != stats[j].Given {
+			return stats[i].Given > stats[j].Given
+		}
+		return stats[i].UserID < stats[j].UserID
+	})
+}
+
+// sortSeasons sorts seasons by their start, oldest first, breaking ties by name
+func sortSeasons(seasons []Season) {
+	sort.Slice(seasons, func(i, j int) bool {
+		if !seasons[i].Start.Equal(seasons[j].Start) {
+			return seasons[i].Start.Before(seasons[j].Start)
+		}
+		return seasons[i].Name < seasons[j].Name
+	})
+}
+
+// sortBadges sorts badges by when they were awarded, oldest first, breaking ties by badge
+func sortBadges(badges []UserBadge) {
+	sort.Slice(badges, func(i, j int) bool {
+		if !badges[i].AwardedAt.Equal(badges[j].AwardedAt) {
+			return badges[i].AwardedAt.Before(badges[j].AwardedAt)
+		}
+		return badges[i].Badge < badges[j].Badge
+	})
+}
+
+// sortGivingStreaks sorts giving streaks by user ID
+func sortGivingStreaks(streaks []GivingStreak) {
+	sort.Slice(streaks, func(i, j int) bool {
+		return streaks[i].UserID < streaks[j].UserID
+	})
+}
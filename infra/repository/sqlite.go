@@ -4,23 +4,109 @@ import (
 	"context"
 	"database/sql"
 	"log/slog"
+	"strings"
+	"time"
 
 	_ "github.com/ncruces/go-sqlite3/driver"
 	_ "github.com/ncruces/go-sqlite3/embed"
 )
 
+// sqliteTimeFormat is the format of CURRENT_TIMESTAMP in SQLite
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+// sqliteEventTimeFormat is the format of point event times, precise enough to tell quick changes apart
+// and still sorted like the times
+const sqliteEventTimeFormat = "2006-01-02 15:04:05.000000000"
+
+// sqliteSchema creates the tables added after user_points
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS user_settings (
+		user_id TEXT PRIMARY KEY,
+		notify BOOLEAN NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS channel_settings (
+		channel_id TEXT PRIMARY KEY,
+		enabled BOOLEAN,
+		allow_minus BOOLEAN,
+		reply_mode TEXT NOT NULL DEFAULT '',
+		locale TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE IF NOT EXISTS point_events (
+		target TEXT NOT NULL,
+		timestamp TEXT NOT NULL,
+		giver TEXT NOT NULL,
+		channel TEXT NOT NULL DEFAULT '',
+		delta INTEGER NOT NULL,
+		dampened BOOLEAN NOT NULL DEFAULT 0,
+		reason TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (target, timestamp)
+	)`,
+	`CREATE INDEX IF NOT EXISTS point_events_timestamp ON point_events (timestamp)`,
+	`CREATE TABLE IF NOT EXISTS giver_stats (
+		user_id TEXT PRIMARY KEY,
+		given INTEGER NOT NULL DEFAULT 0,
+		last_given TIMESTAMP
+	)`,
+	`CREATE TABLE IF NOT EXISTS giver_recipients (
+		giver TEXT NOT NULL,
+		recipient TEXT NOT NULL,
+		PRIMARY KEY (giver, recipient)
+	)`,
+	`CREATE TABLE IF NOT EXISTS given_points (
+		giver TEXT NOT NULL,
+		recipient TEXT NOT NULL,
+		points INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (giver, recipient)
+	)`,
+	`CREATE TABLE IF NOT EXISTS seasons (
+		name TEXT PRIMARY KEY,
+		start TEXT NOT NULL,
+		end TEXT
+	)`,
+	`CREATE TABLE IF NOT EXISTS season_standings (
+		season TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		points INTEGER NOT NULL,
+		is_user BOOLEAN NOT NULL DEFAULT 0,
+		PRIMARY KEY (season, user_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS badges (
+		user_id TEXT NOT NULL,
+		badge TEXT NOT NULL,
+		awarded_at TEXT NOT NULL,
+		PRIMARY KEY (user_id, badge)
+	)`,
+	`CREATE TABLE IF NOT EXISTS giving_streaks (
+		user_id TEXT PRIMARY KEY,
+		current INTEGER NOT NULL DEFAULT 0,
+		longest INTEGER NOT NULL DEFAULT 0,
+		last_day TEXT NOT NULL DEFAULT ''
+	)`,
+}
+
 // SQLiteRepository implements the UserPointsRepository interface using SQLite
 type SQLiteRepository struct {
 	db     *sql.DB
 	logger *slog.Logger
 }
 
+// sqliteInMemory reports whether a database path opens a database private to its connection:
+// an in-memory database, or a temporary one for an empty path
+func sqliteInMemory(dbPath string) bool {
+	return dbPath == "" || strings.Contains(dbPath, ":memory:") || strings.Contains(dbPath, "mode=memory")
+}
+
 // NewSQLiteRepository creates a new SQLiteRepository instance
 func NewSQLiteRepository(dbPath string, logger *slog.Logger) (*SQLiteRepository, error) {
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, err
 	}
+	if sqliteInMemory(dbPath) {
+		// Every connection to an in-memory database opens a new, empty one, so the pool must keep a single
+		// connection for the background work of the bot to see the same tables
+		db.SetMaxOpenConns(1)
+	}
 
 	sqliteRepo := &SQLiteRepository{
 		db:     db,
@@ -56,6 +142,12 @@ func NewSQLiteRepository(dbPath string, logger *slog.Logger) (*SQLiteRepository,
 		logger.Info("Table 'user_points' created successfully")
 	}
 
+	for _, stmt := range sqliteSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := db.Ping(); err != nil {
 		return nil, err
 	}
@@ -63,17 +155,27 @@ func NewSQLiteRepository(dbPath string, logger *slog.Logger) (*SQLiteRepository,
 	return sqliteRepo, nil
 }
 
-// AddPoints adds points to a user
+// sqliteFlooredPoints is the expression of the points to add to the total of a record: the second argument,
+// reduced like flooredPoints when the first argument is true
+const sqliteFlooredPoints = `CASE WHEN ? THEN MAX(points + ?, MIN(points, 0)) - points ELSE ? END`
+
+// AddPoints adds points to a user, keeping the total from going below zero in the same statement
+// when the context floors totals
 func (s *SQLiteRepository) AddPoints(ctx context.Context, userID string, points int, isUser bool) error {
+	floored := floorAtZero(ctx)
+	initial := points
+	if floored {
+		initial = flooredPoints(0, points)
+	}
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO user_points (user_id, points, is_user)
 		VALUES (?, ?, ?)
 		ON CONFLICT (user_id)
 		DO UPDATE SET
-			points = points + ?,
+			points = points + `+sqliteFlooredPoints+`,
 			is_user = ?,
 			last_modified = CURRENT_TIMESTAMP
-	`, userID, points, isUser, points, isUser)
+	`, userID, initial, isUser, floored, points, points, isUser)
 	return err
 }
 
@@ -87,6 +189,686 @@ func (s *SQLiteRepository) GetPoints(ctx context.Context, userID string) (int, e
 	return points, err
 }
 
+// GetUserPoints gets the points record for a user
+func (s *SQLiteRepository) GetUserPoints(ctx context.Context, userID string) (*UserPoints, error) {
+	var userPoints UserPoints
+	err := s.db.QueryRowContext(ctx, `
+		SELECT user_id, points, is_user, last_modified
+		FROM user_points
+		WHERE user_id = ?
+	`, userID).Scan(&userPoints.UserID, &userPoints.Points, &userPoints.IsUser, &userPoints.LastModified)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userPoints, nil
+}
+
+// SetPoints sets the points of a user to the given value
+func (s *SQLiteRepository) SetPoints(ctx context.Context, userID string, points int, isUser bool) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_points (user_id, points, is_user)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			points = ?,
+			is_user = ?,
+			last_modified = CURRENT_TIMESTAMP
+	`, userID, points, isUser, points, isUser)
+	return err
+}
+
+// DeletePoints deletes the points record for a user
+func (s *SQLiteRepository) DeletePoints(ctx context.Context, userID string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM user_points WHERE user_id = ?", userID)
+	return err
+}
+
+// ListPoints lists points records ordered by points, highest first
+func (s *SQLiteRepository) ListPoints(ctx context.Context, limit int) ([]UserPoints, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, points, is_user, last_modified
+		FROM user_points
+		ORDER BY points DESC, user_id
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []UserPoints
+	for rows.Next() {
+		var userPoints UserPoints
+		if err := rows.Scan(&userPoints.UserID, &userPoints.Points, &userPoints.IsUser, &userPoints.LastModified); err != nil {
+			return nil, err
+		}
+		records = append(records, userPoints)
+	}
+	return records, rows.Err()
+}
+
+// GetRank gets the leaderboard position of a user
+func (s *SQLiteRepository) GetRank(ctx context.Context, userID string) (int, error) {
+	if _, err := s.GetUserPoints(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	var rank int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) + 1
+		FROM user_points
+		WHERE points > (SELECT points FROM user_points WHERE user_id = ?)
+	`, userID).Scan(&rank)
+	return rank, err
+}
+
+// ScanPoints calls fn for every points record ordered by user ID
+func (s *SQLiteRepository) ScanPoints(ctx context.Context, fn func(UserPoints) error) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, points, is_user, last_modified
+		FROM user_points
+		ORDER BY user_id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userPoints UserPoints
+		if err := rows.Scan(&userPoints.UserID, &userPoints.Points, &userPoints.IsUser, &userPoints.LastModified); err != nil {
+			return err
+		}
+		if err := fn(userPoints); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// PutUserPoints stores a points record as is
+func (s *SQLiteRepository) PutUserPoints(ctx context.Context, userPoints UserPoints) error {
+	lastModified := userPoints.LastModified
+	if lastModified.IsZero() {
+		lastModified = time.Now()
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_points (user_id, points, is_user, last_modified)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			points = excluded.points,
+			is_user = excluded.is_user,
+			last_modified = excluded.last_modified
+	`, userPoints.UserID, userPoints.Points, userPoints.IsUser, lastModified.UTC().Format(sqliteTimeFormat))
+	return err
+}
+
+// MovePoints stores the points record to and deletes the points record of from in one transaction
+func (s *SQLiteRepository) MovePoints(ctx context.Context, from string, to UserPoints) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_points (user_id, points, is_user)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			points = excluded.points,
+			is_user = excluded.is_user,
+			last_modified = CURRENT_TIMESTAMP
+	`, to.UserID, to.Points, to.IsUser)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_points WHERE user_id = ?", from); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetUserSettings gets the preferences of a user
+func (s *SQLiteRepository) GetUserSettings(ctx context.Context, userID string) (*UserSettings, error) {
+	settings := UserSettings{UserID: userID}
+	err := s.db.QueryRowContext(ctx, "SELECT notify FROM user_settings WHERE user_id = ?", userID).Scan(&settings.Notify)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// PutUserSettings stores the preferences of a user
+func (s *SQLiteRepository) PutUserSettings(ctx context.Context, settings UserSettings) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_settings (user_id, notify)
+		VALUES (?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET notify = excluded.notify
+	`, settings.UserID, settings.Notify)
+	return err
+}
+
+// GetChannelSettings gets the settings of a channel
+func (s *SQLiteRepository) GetChannelSettings(ctx context.Context, channelID string) (*ChannelSettings, error) {
+	settings := ChannelSettings{ChannelID: channelID}
+	var enabled, allowMinus sql.NullBool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT enabled, allow_minus, reply_mode, locale
+		FROM channel_settings
+		WHERE channel_id = ?
+	`, channelID).Scan(&enabled, &allowMinus, &settings.ReplyMode, &settings.Locale)
+	if err == sql.ErrNoRows {
+		return &settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled.Valid {
+		settings.Enabled = &enabled.Bool
+	}
+	if allowMinus.Valid {
+		settings.AllowMinus = &allowMinus.Bool
+	}
+	return &settings, nil
+}
+
+// PutChannelSettings stores the settings of a channel
+func (s *SQLiteRepository) PutChannelSettings(ctx context.Context, settings ChannelSettings) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO channel_settings (channel_id, enabled, allow_minus, reply_mode, locale)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (channel_id)
+		DO UPDATE SET
+			enabled = excluded.enabled,
+			allow_minus = excluded.allow_minus,
+			reply_mode = excluded.reply_mode,
+			locale = excluded.locale
+	`, settings.ChannelID, settings.Enabled, settings.AllowMinus, settings.ReplyMode, settings.Locale)
+	return err
+}
+
+// AddPointEvent records a point change in the history
+func (s *SQLiteRepository) AddPointEvent(ctx context.Context, event PointEvent) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO point_events (target, timestamp, giver, channel, delta, dampened, reason)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (target, timestamp) DO NOTHING
+	`, event.Target, event.Timestamp.UTC().Format(sqliteEventTimeFormat), event.Giver, event.Channel, event.Delta, event.Dampened, event.Reason)
+	return err
+}
+
+// AddPointsWithEvent adds the delta of an event to the points of its target and records the event
+// in one transaction. When the context floors totals, the delta recorded is the one applied, computed
+// from the total by the statement recording the event.
+func (s *SQLiteRepository) AddPointsWithEvent(ctx context.Context, event PointEvent, isUser bool) (PointChange, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return PointChange{}, err
+	}
+	defer tx.Rollback()
+
+	var change PointChange
+	timestamp := event.Timestamp.UTC().Format(sqliteEventTimeFormat)
+	// The SELECT needs a WHERE clause for SQLite to parse the upsert
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO point_events (target, timestamp, giver, channel, delta, dampened, reason)
+		SELECT ?, ?, ?, ?, `+sqliteFlooredPoints+`, ?, ?
+		FROM (SELECT COALESCE((SELECT points FROM user_points WHERE user_id = ?), 0) AS points)
+		WHERE true
+		ON CONFLICT (target, timestamp) DO NOTHING
+		RETURNING delta
+	`, event.Target, timestamp, event.Giver, event.Channel,
+		floorAtZero(ctx), event.Delta, event.Delta, event.Dampened, event.Reason, event.Target).Scan(&change.Delta)
+	if err == sql.ErrNoRows {
+		// The event is already recorded, and its points with it
+		err = tx.QueryRowContext(ctx, `
+			SELECT e.delta, COALESCE(p.points, 0)
+			FROM point_events e
+			LEFT JOIN user_points p ON p.user_id = e.target
+			WHERE e.target = ? AND e.timestamp = ?
+		`, event.Target, timestamp).Scan(&change.Delta, &change.Points)
+		return change, err
+	}
+	if err != nil {
+		return PointChange{}, err
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO user_points (user_id, points, is_user)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			points = points + ?,
+			is_user = ?,
+			last_modified = CURRENT_TIMESTAMP
+		RETURNING points
+	`, event.Target, change.Delta, isUser, change.Delta, isUser).Scan(&change.Points)
+	if err != nil {
+		return PointChange{}, err
+	}
+	return change, tx.Commit()
+}
+
+// ListPointEvents lists the point changes received by a target since the given time
+func (s *SQLiteRepository) ListPointEvents(ctx context.Context, target string, since time.Time) ([]PointEvent, error) {
+	var events []PointEvent
+	err := s.queryPointEvents(ctx, func(event PointEvent) error {
+		events = append(events, event)
+		return nil
+	}, `
+		SELECT target, timestamp, giver, channel, delta, dampened, reason
+		FROM point_events
+		WHERE (? = '' OR target = ?) AND timestamp >= ?
+		ORDER BY timestamp, target
+	`, target, target, since.UTC().Format(sqliteEventTimeFormat))
+	return events, err
+}
+
+// ScanPointEvents calls fn for every point change, oldest first
+func (s *SQLiteRepository) ScanPointEvents(ctx context.Context, fn func(PointEvent) error) error {
+	return s.queryPointEvents(ctx, fn, `
+		SELECT target, timestamp, giver, channel, delta, dampened, reason
+		FROM point_events
+		ORDER BY timestamp, target
+	`)
+}
+
+// GetPointsBetween sums the point changes of a target in the window, using the primary key of point_events
+func (s *SQLiteRepository) GetPointsBetween(ctx context.Context, target string, from, to time.Time) (int, error) {
+	var points int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(delta), 0)
+		FROM point_events
+		WHERE target = ? AND timestamp >= ? AND timestamp < ?
+	`, target, from.UTC().Format(sqliteEventTimeFormat), to.UTC().Format(sqliteEventTimeFormat)).Scan(&points)
+	return points, err
+}
+
+// ListPointsBetween sums the point changes of every target in the window, using the timestamp index of point_events
+func (s *SQLiteRepository) ListPointsBetween(ctx context.Context, from, to time.Time, limit int) ([]UserPoints, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.target, SUM(e.delta) AS window_points, COALESCE(u.is_user, 0)
+		FROM point_events e
+		LEFT JOIN user_points u ON u.user_id = e.target
+		WHERE e.timestamp >= ? AND e.timestamp < ?
+		GROUP BY e.target
+		HAVING window_points != 0
+		ORDER BY window_points DESC, e.target
+		LIMIT ?
+	`, from.UTC().Format(sqliteEventTimeFormat), to.UTC().Format(sqliteEventTimeFormat), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []UserPoints
+	for rows.Next() {
+		var userPoints UserPoints
+		if err := rows.Scan(&userPoints.UserID, &userPoints.Points, &userPoints.IsUser); err != nil {
+			return nil, err
+		}
+		records = append(records, userPoints)
+	}
+	return records, rows.Err()
+}
+
+// queryPointEvents runs a query selecting point events and calls fn for each of them
+func (s *SQLiteRepository) queryPointEvents(ctx context.Context, fn func(PointEvent) error, query string, args ...any) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event PointEvent
+		var timestamp string
+		if err := rows.Scan(&event.Target, &timestamp, &event.Giver, &event.Channel, &event.Delta, &event.Dampened, &event.Reason); err != nil {
+			return err
+		}
+		if event.Timestamp, err = time.Parse(sqliteEventTimeFormat, timestamp); err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// AddGivenPoints adds points given by a user to a target to the counters of the giver
+func (s *SQLiteRepository) AddGivenPoints(ctx context.Context, giver, recipient string, points int) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO giver_stats (user_id, given, last_given)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			given = given + excluded.given,
+			last_given = excluded.last_given
+	`, giver, points, time.Now().UTC().Format(sqliteTimeFormat))
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO giver_recipients (giver, recipient)
+		VALUES (?, ?)
+		ON CONFLICT (giver, recipient) DO NOTHING
+	`, giver, recipient)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO given_points (giver, recipient, points)
+		VALUES (?, ?, ?)
+		ON CONFLICT (giver, recipient)
+		DO UPDATE SET points = points + excluded.points
+	`, giver, recipient, points)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetGiverStats gets the counters of a giver
+func (s *SQLiteRepository) GetGiverStats(ctx context.Context, giver string) (*GiverStats, error) {
+	stats, err := s.queryGivers(ctx, `
+		WHERE s.user_id = ?
+		GROUP BY s.user_id
+	`, giver)
+	if err != nil {
+		return nil, err
+	}
+	if len(stats) == 0 {
+		return &GiverStats{UserID: giver}, nil
+	}
+	return &stats[0], nil
+}
+
+// ListGivers lists the counters of givers ordered by the points given, highest first
+func (s *SQLiteRepository) ListGivers(ctx context.Context, limit int) ([]GiverStats, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	return s.queryGivers(ctx, `
+		GROUP BY s.user_id
+		ORDER BY s.given DESC, s.user_id
+		LIMIT ?
+	`, limit)
+}
+
+// ListGivenPoints lists the targets a giver has given points to with the points given to each, highest first
+func (s *SQLiteRepository) ListGivenPoints(ctx context.Context, giver string, limit int) ([]UserPoints, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT recipient, points
+		FROM given_points
+		WHERE giver = ?
+		ORDER BY points DESC, recipient
+		LIMIT ?
+	`, giver, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []UserPoints
+	for rows.Next() {
+		var record UserPoints
+		if err := rows.Scan(&record.UserID, &record.Points); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// queryGivers selects giver counters with the number of recipients, filtered and ordered by the given clauses
+func (s *SQLiteRepository) queryGivers(ctx context.Context, clauses string, args ...any) ([]GiverStats, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.user_id, s.given, s.last_given, COUNT(r.recipient)
+		FROM giver_stats s
+		LEFT JOIN giver_recipients r ON r.giver = s.user_id
+	`+clauses, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []GiverStats
+	for rows.Next() {
+		var giver GiverStats
+		var lastGiven sql.NullTime
+		if err := rows.Scan(&giver.UserID, &giver.Given, &lastGiven, &giver.Recipients); err != nil {
+			return nil, err
+		}
+		giver.LastGiven = lastGiven.Time
+		stats = append(stats, giver)
+	}
+	return stats, rows.Err()
+}
+
+// PutSeason stores a season, replacing the one with the same name
+func (s *SQLiteRepository) PutSeason(ctx context.Context, season Season) error {
+	var end sql.NullString
+	if !season.End.IsZero() {
+		end = sql.NullString{String: season.End.UTC().Format(sqliteEventTimeFormat), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO seasons (name, start, end)
+		VALUES (?, ?, ?)
+		ON CONFLICT (name)
+		DO UPDATE SET
+			start = excluded.start,
+			end = excluded.end
+	`, season.Name, season.Start.UTC().Format(sqliteEventTimeFormat), end)
+	return err
+}
+
+// ListSeasons lists the seasons ordered by their start, oldest first
+func (s *SQLiteRepository) ListSeasons(ctx context.Context) ([]Season, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, start, end
+		FROM seasons
+		ORDER BY start, name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seasons []Season
+	for rows.Next() {
+		var season Season
+		var start string
+		var end sql.NullString
+		if err := rows.Scan(&season.Name, &start, &end); err != nil {
+			return nil, err
+		}
+		if season.Start, err = time.Parse(sqliteEventTimeFormat, start); err != nil {
+			return nil, err
+		}
+		if end.Valid {
+			if season.End, err = time.Parse(sqliteEventTimeFormat, end.String); err != nil {
+				return nil, err
+			}
+		}
+		seasons = append(seasons, season)
+	}
+	return seasons, rows.Err()
+}
+
+// PutSeasonStandings stores the final points of the targets in a season in one transaction
+func (s *SQLiteRepository) PutSeasonStandings(ctx context.Context, season string, standings []UserPoints) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, standing := range standings {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO season_standings (season, user_id, points, is_user)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT (season, user_id)
+			DO UPDATE SET
+				points = excluded.points,
+				is_user = excluded.is_user
+		`, season, standing.UserID, standing.Points, standing.IsUser)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListSeasonStandings lists the final points of the targets in a season ordered by points, highest first
+func (s *SQLiteRepository) ListSeasonStandings(ctx context.Context, season string, limit int) ([]UserPoints, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, points, is_user
+		FROM season_standings
+		WHERE season = ?
+		ORDER BY points DESC, user_id
+		LIMIT ?
+	`, season, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []UserPoints
+	for rows.Next() {
+		var standing UserPoints
+		if err := rows.Scan(&standing.UserID, &standing.Points, &standing.IsUser); err != nil {
+			return nil, err
+		}
+		standings = append(standings, standing)
+	}
+	return standings, rows.Err()
+}
+
+// AddBadge awards a badge to a user and reports whether it is new
+func (s *SQLiteRepository) AddBadge(ctx context.Context, badge UserBadge) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO badges (user_id, badge, awarded_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id, badge) DO NOTHING
+	`, badge.UserID, badge.Badge, badge.AwardedAt.UTC().Format(sqliteEventTimeFormat))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// ListBadges lists the badges awarded to a user, oldest first
+func (s *SQLiteRepository) ListBadges(ctx context.Context, userID string) ([]UserBadge, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT badge, awarded_at
+		FROM badges
+		WHERE user_id = ?
+		ORDER BY awarded_at, badge
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var badges []UserBadge
+	for rows.Next() {
+		badge := UserBadge{UserID: userID}
+		var awardedAt string
+		if err := rows.Scan(&badge.Badge, &awardedAt); err != nil {
+			return nil, err
+		}
+		if badge.AwardedAt, err = time.Parse(sqliteEventTimeFormat, awardedAt); err != nil {
+			return nil, err
+		}
+		badges = append(badges, badge)
+	}
+	return badges, rows.Err()
+}
+
+// GetGivingStreak gets the giving streak of a user, all zero if there is none
+func (s *SQLiteRepository) GetGivingStreak(ctx context.Context, userID string) (*GivingStreak, error) {
+	streak := &GivingStreak{UserID: userID}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT current, longest, last_day
+		FROM giving_streaks
+		WHERE user_id = ?
+	`, userID).Scan(&streak.Current, &streak.Longest, &streak.LastDay)
+	if err == sql.ErrNoRows {
+		return streak, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return streak, nil
+}
+
+// PutGivingStreak stores the giving streak of a user
+func (s *SQLiteRepository) PutGivingStreak(ctx context.Context, streak GivingStreak) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO giving_streaks (user_id, current, longest, last_day)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			current = excluded.current,
+			longest = excluded.longest,
+			last_day = excluded.last_day
+	`, streak.UserID, streak.Current, streak.Longest, streak.LastDay)
+	return err
+}
+
+// ListGivingStreaks lists the giving streaks of every user ordered by user ID
+func (s *SQLiteRepository) ListGivingStreaks(ctx context.Context) ([]GivingStreak, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, current, longest, last_day
+		FROM giving_streaks
+		ORDER BY user_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var streaks []GivingStreak
+	for rows.Next() {
+		var streak GivingStreak
+		if err := rows.Scan(&streak.UserID, &streak.Current, &streak.Longest, &streak.LastDay); err != nil {
+			return nil, err
+		}
+		streaks = append(streaks, streak)
+	}
+	return streaks, rows.Err()
+}
+
 // Close closes the database connection
 func (s *SQLiteRepository) Close() error {
 	return s.db.Close()
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"os"
+	"slices"
 	"testing"
+	"time"
 
 	"log/slog"
 )
@@ -137,3 +140,619 @@ func TestSQLiteGetPoints(t *testing.T) {
 		t.Errorf("GetPoints() = %v, want 20", points)
 	}
 }
+
+func TestSQLiteSetAndDeletePoints(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := repo.GetUserPoints(ctx, "user1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserPoints() error = %v, want ErrNotFound", err)
+	}
+
+	if err := repo.SetPoints(ctx, "user1", 42, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	if err := repo.SetPoints(ctx, "user1", 7, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+
+	record, err := repo.GetUserPoints(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUserPoints() error = %v", err)
+	}
+	if record.UserID != "user1" || record.Points != 7 || !record.IsUser || record.LastModified.IsZero() {
+		t.Errorf("GetUserPoints() = %+v, want 7 points for user1", record)
+	}
+
+	if err := repo.DeletePoints(ctx, "user1"); err != nil {
+		t.Fatalf("DeletePoints() error = %v", err)
+	}
+	if _, err := repo.GetUserPoints(ctx, "user1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserPoints() after DeletePoints() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLiteMovePoints(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := repo.SetPoints(ctx, "user1", 3, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+	if err := repo.SetPoints(ctx, "user2", 4, true); err != nil {
+		t.Fatalf("SetPoints() error = %v", err)
+	}
+
+	if err := repo.MovePoints(ctx, "user1", UserPoints{UserID: "user2", Points: 7, IsUser: true}); err != nil {
+		t.Fatalf("MovePoints() error = %v", err)
+	}
+	if _, err := repo.GetUserPoints(ctx, "user1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUserPoints() of the source error = %v, want ErrNotFound", err)
+	}
+	record, err := repo.GetUserPoints(ctx, "user2")
+	if err != nil {
+		t.Fatalf("GetUserPoints() error = %v", err)
+	}
+	if record.Points != 7 || !record.IsUser {
+		t.Errorf("GetUserPoints() of the destination = %+v, want 7 points for a user", record)
+	}
+}
+
+func TestSQLiteListPoints(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	for id, points := range map[string]int{"user1": 5, "user2": 10, "sake": -1, "user3": 5} {
+		if err := repo.AddPoints(ctx, id, points, id != "sake"); err != nil {
+			t.Fatalf("AddPoints() error = %v", err)
+		}
+	}
+
+	records, err := repo.ListPoints(ctx, 0)
+	if err != nil {
+		t.Fatalf("ListPoints() error = %v", err)
+	}
+	want := []string{"user2", "user1", "user3", "sake"}
+	if len(records) != len(want) {
+		t.Fatalf("ListPoints() returned %d records, want %d", len(records), len(want))
+	}
+	for i, id := range want {
+		if records[i].UserID != id {
+			t.Errorf("ListPoints()[%d] = %s, want %s", i, records[i].UserID, id)
+		}
+	}
+
+	records, err = repo.ListPoints(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListPoints() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("ListPoints(2) returned %d records, want 2", len(records))
+	}
+
+	for id, want := range map[string]int{"user2": 1, "user1": 2, "user3": 2, "sake": 4} {
+		rank, err := repo.GetRank(ctx, id)
+		if err != nil {
+			t.Fatalf("GetRank(%s) error = %v", id, err)
+		}
+		if rank != want {
+			t.Errorf("GetRank(%s) = %d, want %d", id, rank, want)
+		}
+	}
+	if _, err := repo.GetRank(ctx, "nobody"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetRank() for a missing user error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLiteScanAndPutPoints(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	lastModified := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+
+	if err := repo.PutUserPoints(ctx, UserPoints{UserID: "user2", Points: 3, IsUser: true, LastModified: lastModified}); err != nil {
+		t.Fatalf("PutUserPoints() error = %v", err)
+	}
+	if err := repo.AddPoints(ctx, "user1", 1, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+
+	var records []UserPoints
+	err := repo.ScanPoints(ctx, func(p UserPoints) error {
+		records = append(records, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ScanPoints() error = %v", err)
+	}
+	if len(records) != 2 || records[0].UserID != "user1" || records[1].UserID != "user2" {
+		t.Fatalf("ScanPoints() = %+v, want user1 and user2", records)
+	}
+	if !records[1].LastModified.Equal(lastModified) {
+		t.Errorf("ScanPoints() last modified = %v, want %v", records[1].LastModified, lastModified)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.ScanPoints(ctx, func(p UserPoints) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("ScanPoints() = %v after %d calls, want stop after 1 call", err, calls)
+	}
+}
+
+func TestSQLiteUserSettings(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	settings, err := repo.GetUserSettings(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetUserSettings() error = %v", err)
+	}
+	if settings.UserID != "user1" || settings.Notify {
+		t.Errorf("GetUserSettings() = %+v, want notifications off by default", settings)
+	}
+
+	for _, notify := range []bool{true, false, true} {
+		if err := repo.PutUserSettings(ctx, UserSettings{UserID: "user1", Notify: notify}); err != nil {
+			t.Fatalf("PutUserSettings() error = %v", err)
+		}
+		settings, err := repo.GetUserSettings(ctx, "user1")
+		if err != nil {
+			t.Fatalf("GetUserSettings() error = %v", err)
+		}
+		if settings.Notify != notify {
+			t.Errorf("GetUserSettings() Notify = %v, want %v", settings.Notify, notify)
+		}
+	}
+}
+
+func TestSQLiteChannelSettings(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	settings, err := repo.GetChannelSettings(ctx, "C1")
+	if err != nil {
+		t.Fatalf("GetChannelSettings() error = %v", err)
+	}
+	if settings.ChannelID != "C1" || settings.Enabled != nil || settings.AllowMinus != nil || settings.ReplyMode != "" || settings.Locale != "" {
+		t.Errorf("GetChannelSettings() = %+v, want no fields set", settings)
+	}
+
+	disabled := false
+	if err := repo.PutChannelSettings(ctx, ChannelSettings{ChannelID: "C1", AllowMinus: &disabled, ReplyMode: "thread", Locale: "ja"}); err != nil {
+		t.Fatalf("PutChannelSettings() error = %v", err)
+	}
+	settings, err = repo.GetChannelSettings(ctx, "C1")
+	if err != nil {
+		t.Fatalf("GetChannelSettings() error = %v", err)
+	}
+	if settings.Enabled != nil || settings.AllowMinus == nil || *settings.AllowMinus || settings.ReplyMode != "thread" || settings.Locale != "ja" {
+		t.Errorf("GetChannelSettings() = %+v", settings)
+	}
+}
+
+func TestSQLitePointEvents(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+
+	events := []PointEvent{
+		{Target: "U2", Timestamp: start, Giver: "U1", Channel: "C1", Delta: 1, Reason: "for the review"},
+		{Target: "U1", Timestamp: start.Add(time.Millisecond), Giver: "U2", Channel: "C1", Delta: 1},
+		{Target: "U2", Timestamp: start.Add(time.Hour), Giver: "U1", Channel: "C2", Dampened: true},
+	}
+	for _, event := range events {
+		// Adding an event twice stores it once
+		for i := 0; i < 2; i++ {
+			if err := repo.AddPointEvent(ctx, event); err != nil {
+				t.Fatalf("AddPointEvent() error = %v", err)
+			}
+		}
+	}
+
+	got, err := repo.ListPointEvents(ctx, "", start)
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("ListPointEvents() = %+v, want %d events", got, len(events))
+	}
+	for i, want := range events {
+		if got[i].Target != want.Target || !got[i].Timestamp.Equal(want.Timestamp) || got[i].Giver != want.Giver || got[i].Channel != want.Channel || got[i].Delta != want.Delta || got[i].Dampened != want.Dampened || got[i].Reason != want.Reason {
+			t.Errorf("ListPointEvents()[%d] = %+v, want %+v", i, got[i], want)
+		}
+	}
+
+	got, err = repo.ListPointEvents(ctx, "U2", start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != 1 || !got[0].Dampened {
+		t.Errorf("ListPointEvents(U2, since a minute later) = %+v, want the dampened event", got)
+	}
+
+	count := 0
+	if err := repo.ScanPointEvents(ctx, func(PointEvent) error {
+		count++
+		return nil
+	}); err != nil {
+		t.Fatalf("ScanPointEvents() error = %v", err)
+	}
+	if count != len(events) {
+		t.Errorf("ScanPointEvents() visited %d events, want %d", count, len(events))
+	}
+}
+
+func TestSQLitePointsBetween(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	day := time.Date(2025, 5, 16, 0, 0, 0, 0, time.UTC)
+	for _, target := range []string{"U1", "U2"} {
+		if err := repo.AddPoints(ctx, target, 1, true); err != nil {
+			t.Fatalf("AddPoints() error = %v", err)
+		}
+	}
+	events := []PointEvent{
+		{Target: "U2", Timestamp: day.Add(12 * time.Hour), Giver: "U1", Delta: 1},
+		{Target: "U1", Timestamp: day.Add(12*time.Hour + 30*time.Minute), Giver: "U2", Delta: 1},
+		{Target: "U2", Timestamp: day.Add(24*time.Hour - 30*time.Second), Giver: "U3", Delta: 1},
+		{Target: "U2", Timestamp: day.Add(24*time.Hour + 10*time.Minute), Giver: "U3", Delta: -1},
+		{Target: "sake", Timestamp: time.Date(2025, 5, 31, 18, 0, 0, 0, time.UTC), Giver: "U1", Delta: 2},
+		{Target: "U1", Timestamp: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), Giver: "U2", Delta: 1},
+	}
+	for _, event := range events {
+		// Adding an event twice counts it once
+		for i := 0; i < 2; i++ {
+			if err := repo.AddPointEvent(ctx, event); err != nil {
+				t.Fatalf("AddPointEvent() error = %v", err)
+			}
+		}
+	}
+
+	may := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	pointsTests := []struct {
+		name     string
+		target   string
+		from, to time.Time
+		want     int
+	}{
+		{"day", "U2", day, day.AddDate(0, 0, 1), 2},
+		{"month", "U2", may, june, 1},
+		{"not aligned to hours", "U2", day.Add(11*time.Hour + 45*time.Minute), day.Add(24*time.Hour + 5*time.Minute), 2},
+		{"within an hour", "U1", day.Add(12*time.Hour + 15*time.Minute), day.Add(12*time.Hour + 45*time.Minute), 1},
+		{"no events", "U3", may, june, 0},
+	}
+	for _, tt := range pointsTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetPointsBetween(ctx, tt.target, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetPointsBetween() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetPointsBetween(%s, %v, %v) = %d, want %d", tt.target, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	records, err := repo.ListPointsBetween(ctx, may, june, 0)
+	if err != nil {
+		t.Fatalf("ListPointsBetween() error = %v", err)
+	}
+	want := []UserPoints{{UserID: "sake", Points: 2}, {UserID: "U1", Points: 1, IsUser: true}, {UserID: "U2", Points: 1, IsUser: true}}
+	if len(records) != len(want) {
+		t.Fatalf("ListPointsBetween() = %+v, want %+v", records, want)
+	}
+	for i := range want {
+		if records[i] != want[i] {
+			t.Errorf("ListPointsBetween()[%d] = %+v, want %+v", i, records[i], want[i])
+		}
+	}
+
+	records, err = repo.ListPointsBetween(ctx, day.AddDate(0, 0, 1), june.AddDate(0, 0, 1), 0)
+	if err != nil {
+		t.Fatalf("ListPointsBetween() error = %v", err)
+	}
+	if len(records) != 3 || records[0].UserID != "sake" || records[2].UserID != "U2" || records[2].Points != -1 {
+		t.Errorf("ListPointsBetween() = %+v, want U2 last with -1", records)
+	}
+
+	if records, err := repo.ListPointsBetween(ctx, may, june, 1); err != nil || len(records) != 1 || records[0].UserID != "sake" {
+		t.Errorf("ListPointsBetween(limit 1) = %+v, %v, want sake", records, err)
+	}
+
+	// Events at the ends of a window not aligned to hours are counted
+	records, err = repo.ListPointsBetween(ctx, day.Add(11*time.Hour+45*time.Minute), day.Add(24*time.Hour+5*time.Minute), 0)
+	if err != nil {
+		t.Fatalf("ListPointsBetween() error = %v", err)
+	}
+	if len(records) != 2 || records[0].UserID != "U2" || records[0].Points != 2 || records[1].UserID != "U1" || records[1].Points != 1 {
+		t.Errorf("ListPointsBetween() not aligned to hours = %+v, want U2 with 2 and U1 with 1", records)
+	}
+}
+
+func TestSQLiteAddPointsWithEvent(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	start := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+	events := []PointEvent{
+		{Target: "U2", Timestamp: start, Giver: "U1", Delta: 2, Reason: "for the review"},
+		{Target: "U2", Timestamp: start.Add(time.Minute), Giver: "U3", Delta: -1},
+	}
+	wantChanges := []PointChange{{Delta: 2, Points: 2}, {Delta: -1, Points: 1}}
+	for i, event := range events {
+		// Adding an event twice changes the points once
+		for range 2 {
+			change, err := repo.AddPointsWithEvent(ctx, event, true)
+			if err != nil {
+				t.Fatalf("AddPointsWithEvent() error = %v", err)
+			}
+			if change != wantChanges[i] {
+				t.Errorf("AddPointsWithEvent() = %+v, want %+v", change, wantChanges[i])
+			}
+		}
+	}
+
+	record, err := repo.GetUserPoints(ctx, "U2")
+	if err != nil {
+		t.Fatalf("GetUserPoints() error = %v", err)
+	}
+	if record.Points != 1 || !record.IsUser {
+		t.Errorf("GetUserPoints() = %+v, want 1 point for a user", record)
+	}
+	got, err := repo.ListPointEvents(ctx, "U2", start)
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Delta != 2 || got[0].Reason != "for the review" || got[1].Delta != -1 {
+		t.Errorf("ListPointEvents() = %+v, want both events", got)
+	}
+	if points, err := repo.GetPointsBetween(ctx, "U2", start, start.Add(time.Hour)); err != nil || points != 1 {
+		t.Errorf("GetPointsBetween() = %d, %v, want 1", points, err)
+	}
+}
+
+func TestSQLiteSeasons(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	spring := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	summer := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := CurrentSeason(ctx, repo); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("CurrentSeason() error = %v, want ErrNotFound", err)
+	}
+	if _, err := StartSeason(ctx, repo, "2025-Q2", spring); err != nil {
+		t.Fatalf("StartSeason() error = %v", err)
+	}
+	if _, err := StartSeason(ctx, repo, "other", spring); !errors.Is(err, ErrSeasonInProgress) {
+		t.Errorf("StartSeason() error = %v, want ErrSeasonInProgress", err)
+	}
+
+	if err := repo.AddPoints(ctx, "U1", 5, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	events := []PointEvent{
+		{Target: "U1", Timestamp: spring.Add(-time.Hour), Giver: "U2", Delta: 2},
+		{Target: "U1", Timestamp: spring.AddDate(0, 1, 0), Giver: "U2", Delta: 1},
+		{Target: "sake", Timestamp: spring.AddDate(0, 2, 0), Giver: "U1", Delta: 2},
+		{Target: "U1", Timestamp: summer.Add(time.Hour), Giver: "U2", Delta: 2},
+	}
+	for _, event := range events {
+		if err := repo.AddPointEvent(ctx, event); err != nil {
+			t.Fatalf("AddPointEvent() error = %v", err)
+		}
+	}
+
+	season, standings, err := EndSeason(ctx, repo, summer)
+	if err != nil {
+		t.Fatalf("EndSeason() error = %v", err)
+	}
+	if season.Name != "2025-Q2" || !season.End.Equal(summer) {
+		t.Errorf("EndSeason() season = %+v, want 2025-Q2 ending at %v", season, summer)
+	}
+	want := []UserPoints{{UserID: "sake", Points: 2}, {UserID: "U1", Points: 1, IsUser: true}}
+	if len(standings) != len(want) {
+		t.Fatalf("EndSeason() standings = %+v, want %+v", standings, want)
+	}
+	if _, _, err := EndSeason(ctx, repo, summer); !errors.Is(err, ErrNotFound) {
+		t.Errorf("EndSeason() without a season error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := StartSeason(ctx, repo, "2025-Q2", summer); !errors.Is(err, ErrSeasonExists) {
+		t.Errorf("StartSeason() error = %v, want ErrSeasonExists", err)
+	}
+	if _, err := StartSeason(ctx, repo, "2025-Q3", summer); err != nil {
+		t.Fatalf("StartSeason() error = %v", err)
+	}
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		t.Fatalf("ListSeasons() error = %v", err)
+	}
+	if len(seasons) != 2 || seasons[0].Name != "2025-Q2" || !seasons[0].Start.Equal(spring) ||
+		seasons[1].Name != "2025-Q3" || !seasons[1].End.IsZero() {
+		t.Errorf("ListSeasons() = %+v, want 2025-Q2 then 2025-Q3 in progress", seasons)
+	}
+
+	got, err := repo.ListSeasonStandings(ctx, "2025-Q2", 0)
+	if err != nil {
+		t.Fatalf("ListSeasonStandings() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ListSeasonStandings() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].UserID != want[i].UserID || got[i].Points != want[i].Points || got[i].IsUser != want[i].IsUser {
+			t.Errorf("ListSeasonStandings()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got, err := repo.ListSeasonStandings(ctx, "2025-Q2", 1); err != nil || len(got) != 1 {
+		t.Errorf("ListSeasonStandings(limit 1) = %+v, %v, want 1 record", got, err)
+	}
+	if got, err := repo.ListSeasonStandings(ctx, "unknown", 0); err != nil || len(got) != 0 {
+		t.Errorf("ListSeasonStandings(unknown) = %+v, %v, want none", got, err)
+	}
+}
+
+func TestSQLiteBadges(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	first := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+	awards := []struct {
+		badge   UserBadge
+		wantNew bool
+	}{
+		{UserBadge{UserID: "U1", Badge: "hundred-club", AwardedAt: first.Add(time.Hour)}, true},
+		{UserBadge{UserID: "U1", Badge: "first-plus", AwardedAt: first}, true},
+		{UserBadge{UserID: "U2", Badge: "first-plus", AwardedAt: first}, true},
+		// Awarding a badge again keeps the first award
+		{UserBadge{UserID: "U1", Badge: "first-plus", AwardedAt: first.Add(2 * time.Hour)}, false},
+	}
+	for _, award := range awards {
+		added, err := repo.AddBadge(ctx, award.badge)
+		if err != nil {
+			t.Fatalf("AddBadge() error = %v", err)
+		}
+		if added != award.wantNew {
+			t.Errorf("AddBadge(%+v) = %v, want %v", award.badge, added, award.wantNew)
+		}
+	}
+
+	badges, err := repo.ListBadges(ctx, "U1")
+	if err != nil {
+		t.Fatalf("ListBadges() error = %v", err)
+	}
+	if len(badges) != 2 || badges[0].Badge != "first-plus" || !badges[0].AwardedAt.Equal(first) || badges[1].Badge != "hundred-club" {
+		t.Errorf("ListBadges() = %+v, want first-plus then hundred-club", badges)
+	}
+	if badges, err := repo.ListBadges(ctx, "U3"); err != nil || len(badges) != 0 {
+		t.Errorf("ListBadges(U3) = %+v, %v, want none", badges, err)
+	}
+}
+
+func TestSQLiteGivingStreaks(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	streak, err := repo.GetGivingStreak(ctx, "U1")
+	if err != nil {
+		t.Fatalf("GetGivingStreak() error = %v", err)
+	}
+	if *streak != (GivingStreak{UserID: "U1"}) {
+		t.Errorf("GetGivingStreak() = %+v, want a zero streak", streak)
+	}
+
+	for _, streak := range []GivingStreak{
+		{UserID: "U2", Current: 1, Longest: 4, LastDay: "2025-05-15"},
+		{UserID: "U1", Current: 2, Longest: 2, LastDay: "2025-05-15"},
+		{UserID: "U1", Current: 3, Longest: 3, LastDay: "2025-05-16"},
+	} {
+		if err := repo.PutGivingStreak(ctx, streak); err != nil {
+			t.Fatalf("PutGivingStreak() error = %v", err)
+		}
+	}
+
+	want := GivingStreak{UserID: "U1", Current: 3, Longest: 3, LastDay: "2025-05-16"}
+	if streak, err := repo.GetGivingStreak(ctx, "U1"); err != nil || *streak != want {
+		t.Errorf("GetGivingStreak() = %+v, %v, want %+v", streak, err, want)
+	}
+	streaks, err := repo.ListGivingStreaks(ctx)
+	if err != nil {
+		t.Fatalf("ListGivingStreaks() error = %v", err)
+	}
+	if len(streaks) != 2 || streaks[0] != want || streaks[1].UserID != "U2" || streaks[1].Longest != 4 {
+		t.Errorf("ListGivingStreaks() = %+v, want U1 then U2", streaks)
+	}
+}
+
+func TestSQLiteGiverStats(t *testing.T) {
+	repo, cleanup := setupTestSQLiteRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	stats, err := repo.GetGiverStats(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetGiverStats() error = %v", err)
+	}
+	if stats.UserID != "user1" || stats.Given != 0 || stats.Recipients != 0 || !stats.LastGiven.IsZero() {
+		t.Errorf("GetGiverStats() = %+v, want zero counters", stats)
+	}
+
+	for _, recipient := range []string{"user2", "sake", "user2"} {
+		if err := repo.AddGivenPoints(ctx, "user1", recipient, 1); err != nil {
+			t.Fatalf("AddGivenPoints() error = %v", err)
+		}
+	}
+	if err := repo.AddGivenPoints(ctx, "user2", "user1", 1); err != nil {
+		t.Fatalf("AddGivenPoints() error = %v", err)
+	}
+
+	stats, err = repo.GetGiverStats(ctx, "user1")
+	if err != nil {
+		t.Fatalf("GetGiverStats() error = %v", err)
+	}
+	if stats.Given != 3 || stats.Recipients != 2 || time.Since(stats.LastGiven) > time.Minute {
+		t.Errorf("GetGiverStats() = %+v, want 3 points given to 2 recipients just now", stats)
+	}
+
+	givers, err := repo.ListGivers(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListGivers() error = %v", err)
+	}
+	if len(givers) != 1 || givers[0].UserID != "user1" || givers[0].Recipients != 2 {
+		t.Errorf("ListGivers(1) = %+v, want user1 first", givers)
+	}
+	if givers, err := repo.ListGivers(ctx, 0); err != nil || len(givers) != 2 {
+		t.Errorf("ListGivers(0) = %+v, %v, want every giver", givers, err)
+	}
+
+	given, err := repo.ListGivenPoints(ctx, "user1", 0)
+	if err != nil {
+		t.Fatalf("ListGivenPoints() error = %v", err)
+	}
+	want := []UserPoints{{UserID: "user2", Points: 2}, {UserID: "sake", Points: 1}}
+	if !slices.Equal(given, want) {
+		t.Errorf("ListGivenPoints(0) = %+v, want %+v", given, want)
+	}
+	if given, err := repo.ListGivenPoints(ctx, "user1", 1); err != nil || len(given) != 1 || given[0].UserID != "user2" {
+		t.Errorf("ListGivenPoints(1) = %+v, %v, want user2 only", given, err)
+	}
+}
+
+func TestSQLiteInMemory(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	for _, path := range []string{":memory:", "file::memory:", "file:test?mode=memory"} {
+		repo, err := NewSQLiteRepository(path, logger)
+		if err != nil {
+			t.Fatalf("NewSQLiteRepository(%q) error = %v", path, err)
+		}
+		// A second pooled connection would open a new, empty database
+		if got := repo.db.Stats().MaxOpenConnections; got != 1 {
+			t.Errorf("NewSQLiteRepository(%q) max open connections = %d, want 1", path, got)
+		}
+		repo.Close()
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 
@@ -9,29 +13,145 @@ import (
 	"plusplusbot/infra/repository"
 )
 
+// configPath is the path of the configuration file given by -config or PLUSPLUSBOT_CONFIG
+var configPath string
+
 func main() {
-	botToken := os.Getenv("SLACK_BOT_TOKEN")
-	appToken := os.Getenv("SLACK_APP_TOKEN")
+	flag.StringVar(&configPath, "config", os.Getenv("PLUSPLUSBOT_CONFIG"), "path to the YAML configuration file (PLUSPLUSBOT_CONFIG)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: plusplusbot [-config file] [command]")
+		fmt.Fprintln(flag.CommandLine.Output(), "")
+		fmt.Fprintln(flag.CommandLine.Output(), "Commands: (none) runs the bot, simulate, admin, export, import, import-karma, config check")
+		fmt.Fprintln(flag.CommandLine.Output(), "")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) > 0 {
+		switch args[0] {
+		case "simulate":
+			os.Exit(runSimulate(args[1:]))
+		case "admin":
+			os.Exit(runAdmin(args[1:]))
+		case "export":
+			os.Exit(runExport(args[1:]))
+		case "import":
+			os.Exit(runImport(args[1:]))
+		case "import-karma":
+			os.Exit(runImportKarma(args[1:]))
+		case "config":
+			os.Exit(runConfig(args[1:]))
+		default:
+			flag.Usage()
+			os.Exit(2)
+		}
+	}
+
+	runBot()
+}
 
-	// Initialize logger
+// newLogger creates a logger writing to w
+func newLogger(w io.Writer, debug bool) *slog.Logger {
 	var level slog.Level
-	if os.Getenv("DEBUG") != "" {
+	if debug {
 		level = slog.LevelDebug
 	} else {
 		level = slog.LevelInfo
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
 		Level: level,
 	}))
+}
+
+// loadConfig loads the configuration file and environment variables, and creates a logger writing to w.
+// If the configuration cannot be loaded, the error is logged and nil is returned.
+func loadConfig(w io.Writer) (*config.Config, *slog.Logger) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		newLogger(w, os.Getenv("DEBUG") != "").Error("Failed to load configuration", "error", err)
+		return nil, nil
+	}
+	return cfg, newLogger(w, cfg.Debug)
+}
 
-	// Load configuration
-	cfg := config.NewConfig()
+// botOptions returns the bot options for the configuration
+func botOptions(cfg *config.Config) []bot.Option {
+	channelReplyModes := make(map[string]bot.ReplyMode, len(cfg.ChannelReplyModes))
+	for channel, mode := range cfg.ChannelReplyModes {
+		channelReplyModes[channel] = bot.ReplyMode(mode)
+	}
 
-	if cfg.RepositoryType == config.SQLiteRepository && cfg.SQLiteDBPath == "" {
-		logger.Error("DATABASE_URL environment variable is not set")
+	badges := make([]bot.Badge, 0, len(cfg.Badges))
+	for _, badge := range cfg.Badges {
+		badges = append(badges, bot.Badge{
+			ID:          badge.ID,
+			Name:        badge.Name,
+			Emoji:       badge.Emoji,
+			Description: badge.Description,
+			Rule:        bot.BadgeRule(badge.Rule),
+			Threshold:   badge.Threshold,
+		})
+	}
+	digests := make([]bot.Digest, 0, len(cfg.Digests))
+	for _, digest := range cfg.Digests {
+		digests = append(digests, bot.Digest{Schedule: digest.Schedule, Period: bot.Period(digest.Period), Channel: digest.Channel})
+	}
+
+	return []bot.Option{
+		bot.WithLocale(cfg.Locale),
+		bot.WithChannelLocales(cfg.ChannelLocales),
+		bot.WithUserLocale(cfg.UseUserLocale),
+		bot.WithRichReplies(cfg.RichReplies),
+		bot.WithMilestones(cfg.Milestones, cfg.ShameMilestones),
+		bot.WithMilestoneChannel(cfg.MilestoneChannel),
+		bot.WithReplyMode(bot.ReplyMode(cfg.ReplyMode)),
+		bot.WithChannelReplyModes(channelReplyModes),
+		bot.WithNotificationDelay(cfg.NotificationDelay),
+		bot.WithChannelAllowlist(cfg.ChannelAllowlist),
+		bot.WithBotManagers(cfg.BotManagers),
+		bot.WithAllowMinus(!cfg.DisableMinus),
+		bot.WithSelfVotePolicy(bot.SelfVotePolicy(cfg.SelfVote)),
+		bot.WithTargetOwners(cfg.TargetOwners),
+		bot.WithCollusionDetection(bot.CollusionDetection{
+			Threshold:   cfg.CollusionThreshold,
+			Window:      cfg.CollusionWindow,
+			Dampen:      cfg.CollusionDampen,
+			ReportUsers: cfg.CollusionReportUsers,
+		}),
+		bot.WithTimezone(cfg.Location()),
+		bot.WithDigests(digests),
+		bot.WithBadges(badges),
+		bot.WithStreakNudges(bot.StreakNudges{Schedule: cfg.StreakNudgeSchedule, MinStreak: cfg.StreakNudgeMin}),
+		bot.WithDecay(bot.Decay{HalfLife: cfg.DecayHalfLife, Rate: cfg.DecayRate, Period: bot.Period(cfg.DecayPeriod)}),
+		bot.WithSeasons(bot.Seasons{Schedule: cfg.SeasonSchedule, Channel: cfg.SeasonChannel}),
+	}
+}
+
+// runBot starts the Slack bot
+func runBot() {
+	// Load configuration and initialize logger
+	cfg, logger := loadConfig(os.Stdout)
+	if cfg == nil {
 		os.Exit(1)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	// Load messages and reload them when they change
+	if cfg.MessagesPath != "" {
+		m, err := bot.LoadCatalog(cfg.MessagesPath)
+		if err != nil {
+			logger.Error("Failed to load messages", "error", err)
+			os.Exit(1)
+		}
+		bot.SetCatalog(m)
+		go bot.WatchMessages(context.Background(), cfg.MessagesPath, cfg.MessagesReloadInterval, logger)
+	}
+
 	// Initialize repository
 	repo, err := repository.NewRepository(cfg, logger)
 	if err != nil {
@@ -40,8 +160,7 @@ func main() {
 	}
 
 	// Initialize bot
-	verbose := os.Getenv("DEBUG") != ""
-	bot, err := bot.New(botToken, appToken, repo, verbose, logger)
+	bot, err := bot.New(cfg.SlackBotToken, cfg.SlackAppToken, repo, cfg.Debug, logger, botOptions(cfg)...)
 	if err != nil {
 		logger.Error("Failed to create bot", "error", err)
 		os.Exit(1)
@@ -0,0 +1,172 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// descriptors are the shorthands accepted in place of the five fields
+var descriptors = map[string]string{
+	"@hourly":    "0 * * * *",
+	"@daily":     "0 0 * * *",
+	"@weekly":    "0 0 * * 1",
+	"@monthly":   "0 0 1 * *",
+	"@quarterly": "0 0 1 1,4,7,10 *",
+	"@yearly":    "0 0 1 1 *",
+}
+
+// field is the range of values of a schedule field
+type field struct {
+	name     string
+	min, max int
+}
+
+// fields are the five fields of a schedule in order
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Schedule is a cron-like schedule: minute, hour, day of month, month and day of week,
+// like "0 9 * * 1" for every Monday at 9:00
+type Schedule struct {
+	minutes  []bool
+	hours    []bool
+	days     []bool
+	months   []bool
+	weekdays []bool
+	// anyDay and anyWeekday are set when the day of month or the day of week is "*"
+	anyDay     bool
+	anyWeekday bool
+}
+
+// Parse parses a schedule of five space-separated fields, each "*", a number, a range "a-b" or a list "a,b",
+// optionally with a step like "*/15". Sunday is 0 or 7 in the day of week. The descriptors @hourly, @daily,
+// @weekly (Monday at midnight), @monthly, @quarterly and @yearly are also accepted.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("schedule %q: expected %d fields, got %d", spec, len(fields), len(parts))
+	}
+
+	values := make([][]bool, len(fields))
+	for i, f := range fields {
+		set, err := parseField(parts[i], f)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", spec, err)
+		}
+		values[i] = set
+	}
+	// Sunday can be written as 7
+	values[4][0] = values[4][0] || values[4][7]
+
+	return &Schedule{
+		minutes:    values[0],
+		hours:      values[1],
+		days:       values[2],
+		months:     values[3],
+		weekdays:   values[4],
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+	}, nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into the set of matching values
+func parseField(s string, f field) ([]bool, error) {
+	set := make([]bool, f.max+1)
+	for _, item := range strings.Split(s, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("%s: invalid step %q", f.name, stepPart)
+			}
+			step = n
+		}
+
+		low, high := f.min, f.max
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseValue(first, f); err != nil {
+				return nil, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseValue(last, f); err != nil {
+					return nil, err
+				}
+				if high < low {
+					return nil, fmt.Errorf("%s: invalid range %q", f.name, rangePart)
+				}
+			} else if hasStep {
+				// "5/15" means from 5 to the end in steps of 15
+				high = f.max
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// parseValue parses a single value of a field
+func parseValue(s string, f field) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: %q is not between %d and %d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// dayMatches reports whether the schedule fires on the day of t. Like cron, when both the day of month
+// and the day of week are restricted, either of them matching is enough.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	day, weekday := s.days[t.Day()], s.weekdays[t.Weekday()]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// Next returns the first time after t the schedule fires, in the location of t,
+// or the zero time if it never fires within five years, like on February 30
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !s.months[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hours[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
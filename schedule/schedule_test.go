@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"", "expected 5 fields"},
+		{"0 9 * *", "expected 5 fields"},
+		{"60 * * * *", "minute"},
+		{"0 24 * * *", "hour"},
+		{"0 0 0 * *", "day of month"},
+		{"0 0 * 13 *", "month"},
+		{"0 0 * * 8", "day of week"},
+		{"*/0 * * * *", "invalid step"},
+		{"0 5-1 * * *", "invalid range"},
+		{"@fortnightly", "expected 5 fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := Parse(tt.spec)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse(%q) error = %v, want containing %q", tt.spec, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	// Friday
+	from := time.Date(2025, 5, 16, 12, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", from, time.Date(2025, 5, 16, 12, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", from, time.Date(2025, 5, 16, 12, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1", from, time.Date(2025, 5, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", from, time.Date(2025, 5, 19, 9, 0, 0, 0, time.UTC)},
+		{"30 12 * * *", from, time.Date(2025, 5, 17, 12, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", from, time.Date(2025, 5, 18, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", from, time.Date(2025, 5, 19, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", from, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"@quarterly", from, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", from, time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week matches
+		{"0 0 1 * 0", from, time.Date(2025, 5, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1", from.In(tokyo), time.Date(2025, 5, 19, 9, 0, 0, 0, tokyo)},
+		{"0 0 30 2 *", from, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"plusplusbot/bot"
+	"plusplusbot/infra/config"
+	"plusplusbot/infra/repository"
+)
+
+// runSimulate runs the bot's message handlers against lines read from stdin or a script file
+// and prints the replies, without connecting to Slack
+func runSimulate(args []string) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: plusplusbot simulate [options] [script]")
+		fmt.Fprintln(fs.Output(), "")
+		fmt.Fprintln(fs.Output(), "Reads lines like \"U123 #general: <@U456>++\" from the script file (or stdin)")
+		fmt.Fprintln(fs.Output(), "and prints the bot's replies.")
+		fmt.Fprintln(fs.Output(), "")
+		fs.PrintDefaults()
+	}
+	repoType := fs.String("repository", "", "repository type (sqlite or dynamodb); defaults to REPOSITORY_TYPE")
+	dbPath := fs.String("database", "", "SQLite database path; defaults to DATABASE_URL, or an in-memory database")
+	seed := fs.Int64("seed", 1, "random seed used to pick reply texts")
+	bots := fs.String("bots", "", "comma-separated user IDs to treat as bot users")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	// Logs go to stderr so that the replies on stdout can be compared against golden files
+	cfg, logger := loadConfig(os.Stderr)
+	if cfg == nil {
+		return 1
+	}
+	if *repoType != "" {
+		cfg.RepositoryType = config.RepositoryType(*repoType)
+	}
+	if *dbPath != "" {
+		cfg.SQLiteDBPath = *dbPath
+	}
+	if cfg.RepositoryType == config.SQLiteRepository && cfg.SQLiteDBPath == "" {
+		cfg.SQLiteDBPath = ":memory:"
+	}
+	if err := cfg.Validate(); err != nil {
+		logger.Error("Invalid configuration", "error", err)
+		return 1
+	}
+
+	if cfg.MessagesPath != "" {
+		m, err := bot.LoadCatalog(cfg.MessagesPath)
+		if err != nil {
+			logger.Error("Failed to load messages", "error", err)
+			return 1
+		}
+		bot.SetCatalog(m)
+	}
+
+	repo, err := repository.NewRepository(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to create repository", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := repo.Close(); err != nil {
+			logger.Error("Failed to close repository", "error", err)
+		}
+	}()
+
+	var input io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			logger.Error("Failed to open script", "error", err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+	}
+
+	sim := bot.NewSimulator(repo, os.Stdout, *seed, logger, botOptions(cfg)...)
+	if *bots != "" {
+		sim.SetBotUsers(strings.Split(*bots, ",")...)
+	}
+
+	if err := sim.Run(input); err != nil {
+		logger.Error("Simulation failed", "error", err)
+		return 1
+	}
+	return 0
+}
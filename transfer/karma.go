@@ -0,0 +1,403 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack"
+)
+
+// slackUserIDPattern matches Slack user IDs
+var slackUserIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]{2,}$`)
+
+// copyThingPattern matches the start of the COPY block of the pluspl.us "thing" table in a pg_dump
+var copyThingPattern = regexp.MustCompile(`^COPY (?:[\w"]+\.)?"?thing"? \(([^)]*)\) FROM stdin;$`)
+
+// KarmaEntry is a score read from another karma bot
+type KarmaEntry struct {
+	// Name is the name the other bot kept the score under: a user ID, a user name or a thing
+	Name string
+
+	// Points is the score
+	Points int
+
+	// IsUser indicates whether the other bot knew the entry to be a user
+	IsUser bool
+
+	// IsThing indicates whether the other bot knew the entry not to be a user
+	IsThing bool
+}
+
+// plusplusThing is a row of the pluspl.us "thing" table
+type plusplusThing struct {
+	Item   string `json:"item"`
+	Points int    `json:"points"`
+	User   bool   `json:"user"`
+	TeamID string `json:"team_id"`
+}
+
+// ReadPlusplusDump reads the "thing" table from a plain-text pg_dump of a pluspl.us database.
+// If teamID is not empty, only the rows of that team are read.
+func ReadPlusplusDump(r io.Reader, teamID string) ([]KarmaEntry, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var entries []KarmaEntry
+	var columns map[string]int
+	found := false
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		if columns == nil {
+			matches := copyThingPattern.FindStringSubmatch(text)
+			if matches == nil {
+				continue
+			}
+			columns = map[string]int{}
+			for i, name := range strings.Split(matches[1], ",") {
+				columns[strings.Trim(strings.TrimSpace(name), `"`)] = i
+			}
+			for _, name := range []string{"item", "points", "user"} {
+				if _, ok := columns[name]; !ok {
+					return nil, fmt.Errorf("line %d: thing table has no %q column", line, name)
+				}
+			}
+			found = true
+			continue
+		}
+
+		if text == `\.` {
+			columns = nil
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != len(columns) {
+			return nil, fmt.Errorf("line %d: expected %d columns, got %d", line, len(columns), len(fields))
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || fields[i] == `\N` {
+				return ""
+			}
+			return fields[i]
+		}
+
+		points, err := strconv.Atoi(field("points"))
+		if err != nil && field("points") != "" {
+			return nil, fmt.Errorf("line %d: invalid points: %w", line, err)
+		}
+		thing := plusplusThing{
+			Item:   field("item"),
+			Points: points,
+			User:   field("user") == "t" || field("user") == "true",
+			TeamID: field("team_id"),
+		}
+		if entry, ok := thing.entry(teamID); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("no COPY block for the thing table found")
+	}
+	return entries, nil
+}
+
+// ReadPlusplusJSON reads a JSON export of the pluspl.us "thing" table: an array of
+// objects with item, points, user and team_id fields.
+// If teamID is not empty, only the rows of that team are read.
+func ReadPlusplusJSON(r io.Reader, teamID string) ([]KarmaEntry, error) {
+	var things []plusplusThing
+	if err := json.NewDecoder(r).Decode(&things); err != nil {
+		return nil, err
+	}
+
+	var entries []KarmaEntry
+	for _, thing := range things {
+		if entry, ok := thing.entry(teamID); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// entry converts a pluspl.us row into a KarmaEntry, skipping rows of other teams
+func (t plusplusThing) entry(teamID string) (KarmaEntry, bool) {
+	if teamID != "" && t.TeamID != teamID {
+		return KarmaEntry{}, false
+	}
+	if t.Item == "" {
+		return KarmaEntry{}, false
+	}
+
+	name := t.Item
+	// pluspl.us keeps user IDs lowercased
+	if t.User && slackUserIDPattern.MatchString(strings.ToUpper(name)) {
+		name = strings.ToUpper(name)
+	}
+	return KarmaEntry{Name: name, Points: t.Points, IsUser: t.User, IsThing: !t.User}, true
+}
+
+// ReadHubotBrain reads karma scores from a Hubot brain JSON dump. Scores are looked up under
+// "karma" (hubot-karma), "plusPlus.scores" (hubot-plusplus) and "_private.karma";
+// a flat object of name to score is accepted as well.
+func ReadHubotBrain(r io.Reader) ([]KarmaEntry, error) {
+	var brain map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&brain); err != nil {
+		return nil, err
+	}
+
+	scores, err := findHubotScores(brain)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]KarmaEntry, 0, len(scores))
+	for name, points := range scores {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+		if name == "" {
+			continue
+		}
+		entries = append(entries, KarmaEntry{Name: name, Points: points})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// findHubotScores finds the karma scores in a Hubot brain
+func findHubotScores(brain map[string]json.RawMessage) (map[string]int, error) {
+	lookup := func(data map[string]json.RawMessage, path ...string) (map[string]int, bool) {
+		for _, key := range path[:len(path)-1] {
+			raw, ok := data[key]
+			if !ok {
+				return nil, false
+			}
+			data = nil
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, false
+			}
+		}
+		raw, ok := data[path[len(path)-1]]
+		if !ok {
+			return nil, false
+		}
+		var scores map[string]int
+		if err := json.Unmarshal(raw, &scores); err != nil {
+			return nil, false
+		}
+		return scores, true
+	}
+
+	for _, path := range [][]string{{"karma"}, {"plusPlus", "scores"}, {"_private", "karma"}} {
+		if scores, ok := lookup(brain, path...); ok {
+			return scores, nil
+		}
+	}
+
+	scores := map[string]int{}
+	for name, raw := range brain {
+		var points int
+		if err := json.Unmarshal(raw, &points); err != nil {
+			return nil, errors.New("no karma scores found in the Hubot brain")
+		}
+		scores[name] = points
+	}
+	return scores, nil
+}
+
+// UserDirectory resolves user names to Slack user IDs
+type UserDirectory struct {
+	byID   map[string]slack.User
+	byName map[string][]string
+}
+
+// NewUserDirectory creates a UserDirectory from the result of users.list
+func NewUserDirectory(users []slack.User) *UserDirectory {
+	d := &UserDirectory{
+		byID:   map[string]slack.User{},
+		byName: map[string][]string{},
+	}
+	for _, u := range users {
+		if u.Deleted {
+			continue
+		}
+		d.byID[u.ID] = u
+		seen := map[string]bool{}
+		for _, name := range []string{u.Name, u.Profile.DisplayName, u.RealName} {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			d.byName[key] = append(d.byName[key], u.ID)
+		}
+	}
+	return d
+}
+
+// Resolve returns the user ID for a user ID or name, and whether the user is a human rather than a bot.
+// Names shared by several users are not resolved.
+func (d *UserDirectory) Resolve(name string) (string, bool, bool) {
+	if d == nil {
+		if slackUserIDPattern.MatchString(name) {
+			return name, true, true
+		}
+		return "", false, false
+	}
+
+	if u, ok := d.byID[name]; ok {
+		return u.ID, !u.IsBot, true
+	}
+	ids := d.byName[strings.ToLower(name)]
+	if len(ids) != 1 {
+		return "", false, false
+	}
+	return ids[0], !d.byID[ids[0]].IsBot, true
+}
+
+// MergeItem is a single change of a merge plan
+type MergeItem struct {
+	// Target is the ID the points are added to
+	Target string
+
+	// Sources are the names the points were kept under in the other bot
+	Sources []string
+
+	// IsUser indicates whether the target is a user
+	IsUser bool
+
+	// Points is the number of points to add
+	Points int
+
+	// Current is the current number of points of the target
+	Current int
+}
+
+// MergePlan describes how karma from another bot will be merged into the repository
+type MergePlan struct {
+	// Items are the changes to apply, ordered by target
+	Items []MergeItem
+
+	// Unmapped are the user entries that could not be resolved to a Slack user
+	Unmapped []KarmaEntry
+}
+
+// PlanMerge resolves entries to targets and computes the changes needed to merge them into the repository.
+// Entries that resolve to a Slack user are added to that user, and entries the other bot knew not to be
+// users, or that do not look like users, are treated as things (emoji names).
+// Entries resolving to the same target are summed.
+func PlanMerge(ctx context.Context, repo repository.UserPointsRepository, entries []KarmaEntry, dir *UserDirectory) (*MergePlan, error) {
+	plan := &MergePlan{}
+	items := map[string]*MergeItem{}
+
+	for _, entry := range entries {
+		target, isUser, ok := "", false, false
+		if !entry.IsThing {
+			target, isUser, ok = dir.Resolve(entry.Name)
+		}
+		if !ok {
+			if entry.IsUser {
+				plan.Unmapped = append(plan.Unmapped, entry)
+				continue
+			}
+			target, isUser = strings.Trim(entry.Name, ":"), false
+		}
+
+		item, ok := items[target]
+		if !ok {
+			current, err := repo.GetPoints(ctx, target)
+			if err != nil {
+				return nil, err
+			}
+			item = &MergeItem{Target: target, IsUser: isUser, Current: current}
+			items[target] = item
+		}
+		item.Sources = append(item.Sources, entry.Name)
+		item.Points += entry.Points
+	}
+
+	for _, item := range items {
+		plan.Items = append(plan.Items, *item)
+	}
+	sort.Slice(plan.Items, func(i, j int) bool { return plan.Items[i].Target < plan.Items[j].Target })
+	return plan, nil
+}
+
+// WriteReport writes a human-readable summary of the plan
+func (p *MergePlan) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tIS_USER\tSOURCES\tCURRENT\tADD\tNEW")
+	for _, item := range p.Items {
+		fmt.Fprintf(tw, "%s\t%t\t%s\t%d\t%+d\t%d\n",
+			item.Target, item.IsUser, strings.Join(item.Sources, ","), item.Current, item.Points, item.Current+item.Points)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(p.Unmapped) > 0 {
+		fmt.Fprintf(w, "\n%d user(s) could not be mapped to a Slack user and will be skipped:\n", len(p.Unmapped))
+		for _, entry := range p.Unmapped {
+			fmt.Fprintf(w, "  %s (%d)\n", entry.Name, entry.Points)
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d target(s) to update\n", len(p.Items))
+	return err
+}
+
+// ErrPlanOutdated is returned when a target has changed since its merge plan was made
+var ErrPlanOutdated = errors.New("target changed since the merge was planned")
+
+// Apply sets the planned totals in the repository, and calls applied, if set, after writing each item.
+// The whole plan is checked before anything is written: it fails with ErrPlanOutdated if a target no longer
+// has the points the plan was made from. Targets that already have their planned total are skipped, so that
+// applying a plan again after a failure only writes the remaining items.
+func (p *MergePlan) Apply(ctx context.Context, repo repository.UserPointsRepository, applied func(MergeItem) error) error {
+	var pending []MergeItem
+	for _, item := range p.Items {
+		if item.Target == "" {
+			return errors.New("target is empty")
+		}
+		current, err := repo.GetPoints(ctx, item.Target)
+		if err != nil {
+			return fmt.Errorf("%s: %w", item.Target, err)
+		}
+		switch current {
+		case item.Current + item.Points:
+			// Already applied
+		case item.Current:
+			pending = append(pending, item)
+		default:
+			return fmt.Errorf("%s: %w: planned from %d points, now %d", item.Target, ErrPlanOutdated, item.Current, current)
+		}
+	}
+
+	for _, item := range pending {
+		total := repository.UserPoints{UserID: item.Target, Points: item.Current + item.Points, IsUser: item.IsUser}
+		if err := repo.PutUserPoints(ctx, total); err != nil {
+			return fmt.Errorf("%s: %w", item.Target, err)
+		}
+		if applied != nil {
+			if err := applied(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"plusplusbot/infra/repository"
+
+	"github.com/slack-go/slack"
+)
+
+const plusplusDump = `--
+-- PostgreSQL database dump
+--
+
+COPY public.team (id, team_name) FROM stdin;
+T1	pepabo
+\.
+
+COPY public.thing (id, item, points, "user", team_id, show_in_global_leaderboard) FROM stdin;
+1	u111aaa	12	t	T1	t
+2	pizza	3	f	T1	t
+3	alice	5	t	T1	t
+4	u999zzz	100	t	T2	t
+5	ghost	2	t	T1	\N
+\.
+`
+
+func TestReadPlusplusDump(t *testing.T) {
+	entries, err := ReadPlusplusDump(strings.NewReader(plusplusDump), "T1")
+	if err != nil {
+		t.Fatalf("ReadPlusplusDump() error = %v", err)
+	}
+
+	want := []KarmaEntry{
+		{Name: "U111AAA", Points: 12, IsUser: true},
+		{Name: "pizza", Points: 3, IsThing: true},
+		{Name: "alice", Points: 5, IsUser: true},
+		{Name: "ghost", Points: 2, IsUser: true},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("ReadPlusplusDump() = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("ReadPlusplusDump()[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	if _, err := ReadPlusplusDump(strings.NewReader("SELECT 1;\n"), ""); err == nil {
+		t.Error("ReadPlusplusDump() without a thing table should fail")
+	}
+}
+
+func TestReadPlusplusJSON(t *testing.T) {
+	input := `[{"item":"u111aaa","points":12,"user":true,"team_id":"T1"},{"item":"pizza","points":3,"user":false,"team_id":"T2"}]`
+
+	entries, err := ReadPlusplusJSON(strings.NewReader(input), "")
+	if err != nil {
+		t.Fatalf("ReadPlusplusJSON() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != "U111AAA" || !entries[0].IsUser || !entries[1].IsThing {
+		t.Errorf("ReadPlusplusJSON() = %+v", entries)
+	}
+}
+
+func TestReadHubotBrain(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"hubot-karma", `{"users":{},"karma":{"alice":5,"coffee":-1}}`},
+		{"hubot-plusplus", `{"plusPlus":{"scores":{"@alice":5,"coffee":-1},"reasons":{}}}`},
+		{"private", `{"_private":{"karma":{"alice":5,"coffee":-1}}}`},
+		{"flat", `{"alice":5,"coffee":-1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ReadHubotBrain(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadHubotBrain() error = %v", err)
+			}
+			want := []KarmaEntry{{Name: "alice", Points: 5}, {Name: "coffee", Points: -1}}
+			if len(entries) != len(want) || entries[0] != want[0] || entries[1] != want[1] {
+				t.Errorf("ReadHubotBrain() = %+v, want %+v", entries, want)
+			}
+		})
+	}
+
+	if _, err := ReadHubotBrain(strings.NewReader(`{"users":{"U1":{"name":"alice"}}}`)); err == nil {
+		t.Error("ReadHubotBrain() without scores should fail")
+	}
+}
+
+func TestUserDirectoryResolve(t *testing.T) {
+	dir := NewUserDirectory([]slack.User{
+		{ID: "U111AAA", Name: "alice", RealName: "Alice Liddell"},
+		{ID: "U222BBB", Name: "bob", Profile: slack.UserProfile{DisplayName: "Bobby"}},
+		{ID: "U333CCC", Name: "chris", Profile: slack.UserProfile{DisplayName: "twin"}},
+		{ID: "U444DDD", Name: "christine", Profile: slack.UserProfile{DisplayName: "twin"}},
+		{ID: "B555EEE", Name: "deploybot", IsBot: true},
+		{ID: "U666FFF", Name: "gone", Deleted: true},
+	})
+
+	tests := []struct {
+		name       string
+		wantID     string
+		wantIsUser bool
+		wantOK     bool
+	}{
+		{"U111AAA", "U111AAA", true, true},
+		{"alice", "U111AAA", true, true},
+		{"Alice Liddell", "U111AAA", true, true},
+		{"bobby", "U222BBB", true, true},
+		{"twin", "", false, false},
+		{"deploybot", "B555EEE", false, true},
+		{"gone", "", false, false},
+		{"nobody", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, isUser, ok := dir.Resolve(tt.name)
+			if id != tt.wantID || isUser != tt.wantIsUser || ok != tt.wantOK {
+				t.Errorf("Resolve(%q) = (%q, %v, %v), want (%q, %v, %v)", tt.name, id, isUser, ok, tt.wantID, tt.wantIsUser, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPlanMergeAndApply(t *testing.T) {
+	repo, cleanup := setupTestRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := repo.AddPoints(ctx, "U111AAA", 10, true); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+
+	dir := NewUserDirectory([]slack.User{{ID: "U111AAA", Name: "alice"}})
+	entries := []KarmaEntry{
+		{Name: "U111AAA", Points: 12, IsUser: true},
+		{Name: "alice", Points: 5},
+		{Name: "pizza", Points: 3, IsThing: true},
+		{Name: "ghost", Points: 2, IsUser: true},
+	}
+
+	plan, err := PlanMerge(ctx, repo, entries, dir)
+	if err != nil {
+		t.Fatalf("PlanMerge() error = %v", err)
+	}
+	if len(plan.Items) != 2 || len(plan.Unmapped) != 1 {
+		t.Fatalf("PlanMerge() = %+v, want 2 items and 1 unmapped entry", plan)
+	}
+	alice := plan.Items[0]
+	if alice.Target != "U111AAA" || alice.Points != 17 || alice.Current != 10 || !alice.IsUser || len(alice.Sources) != 2 {
+		t.Errorf("PlanMerge() item = %+v, want 17 points added to U111AAA from 2 sources", alice)
+	}
+
+	var report bytes.Buffer
+	if err := plan.WriteReport(&report); err != nil {
+		t.Fatalf("WriteReport() error = %v", err)
+	}
+	for _, want := range []string{"U111AAA", "pizza", "ghost", "2 target(s)"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("WriteReport() = %q, want to contain %q", report.String(), want)
+		}
+	}
+
+	var applied []string
+	recordApplied := func(item MergeItem) error {
+		applied = append(applied, item.Target)
+		return nil
+	}
+	if err := plan.Apply(ctx, repo, recordApplied); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if !slices.Equal(applied, []string{"U111AAA", "pizza"}) {
+		t.Errorf("Apply() applied %v, want U111AAA and pizza", applied)
+	}
+	if points, _ := repo.GetPoints(ctx, "U111AAA"); points != 27 {
+		t.Errorf("GetPoints(U111AAA) = %v, want 27", points)
+	}
+	record, err := repo.GetUserPoints(ctx, "pizza")
+	if err != nil {
+		t.Fatalf("GetUserPoints() error = %v", err)
+	}
+	if record.Points != 3 || record.IsUser {
+		t.Errorf("GetUserPoints(pizza) = %+v, want 3 points for a thing", record)
+	}
+}
+
+func TestApplyMergePlanChecksFirst(t *testing.T) {
+	sqlite, cleanup := setupTestRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	// Negative points are disabled, as they may be in the workspace the scores are merged into
+	repo := repository.NewPolicyRepository(sqlite, repository.Policy{})
+	entries := []KarmaEntry{
+		{Name: "apple", Points: 4, IsThing: true},
+		{Name: "pear", Points: -2, IsThing: true},
+		{Name: "plum", Points: 1, IsThing: true},
+	}
+	plan, err := PlanMerge(ctx, repo, entries, nil)
+	if err != nil {
+		t.Fatalf("PlanMerge() error = %v", err)
+	}
+
+	// A target changed after planning fails the whole plan before anything is written
+	if err := repo.AddPoints(ctx, "plum", 5, false); err != nil {
+		t.Fatalf("AddPoints() error = %v", err)
+	}
+	if err := plan.Apply(ctx, repo, nil); !errors.Is(err, ErrPlanOutdated) {
+		t.Fatalf("Apply() error = %v, want ErrPlanOutdated", err)
+	}
+	if points, _ := repo.GetPoints(ctx, "apple"); points != 0 {
+		t.Errorf("GetPoints(apple) after a failed Apply() = %d, want 0", points)
+	}
+
+	if plan, err = PlanMerge(ctx, repo, entries, nil); err != nil {
+		t.Fatalf("PlanMerge() error = %v", err)
+	}
+	// A negative total is refused by the policy, and applying the plan again once it allows them finishes it
+	if err := plan.Apply(ctx, repo, nil); !errors.Is(err, repository.ErrMinusDisabled) {
+		t.Fatalf("Apply() error = %v, want ErrMinusDisabled", err)
+	}
+	repo = repository.NewPolicyRepository(sqlite, repository.DefaultPolicy)
+	// Applying the same plan twice sets the same totals
+	for range 2 {
+		if err := plan.Apply(ctx, repo, nil); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+	}
+	for target, want := range map[string]int{"apple": 4, "pear": -2, "plum": 6} {
+		if points, _ := repo.GetPoints(ctx, target); points != want {
+			t.Errorf("GetPoints(%s) = %d, want %d", target, points, want)
+		}
+	}
+}
@@ -0,0 +1,506 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"plusplusbot/infra/repository"
+)
+
+// Format is a data format for exported points
+type Format string
+
+const (
+	// CSVFormat is comma-separated values with a header row
+	CSVFormat Format = "csv"
+
+	// JSONLinesFormat is one JSON object per line
+	JSONLinesFormat Format = "jsonl"
+)
+
+// csvHeader is the header row of the CSV format
+var csvHeader = []string{"target", "points", "is_user", "last_modified"}
+
+// pointsRecordType is the type of JSON Lines records holding the points of a target
+const pointsRecordType = "points"
+
+// eventRecordType is the type of JSON Lines records holding a point change in the history
+const eventRecordType = "event"
+
+// seasonRecordType is the type of JSON Lines records holding a season
+const seasonRecordType = "season"
+
+// standingRecordType is the type of JSON Lines records holding the final points of a target in a season
+const standingRecordType = "standing"
+
+// badgeRecordType is the type of JSON Lines records holding a badge awarded to a user
+const badgeRecordType = "badge"
+
+// streakRecordType is the type of JSON Lines records holding the giving streak of a user
+const streakRecordType = "streak"
+
+// jsonRecord is a single line of the JSON Lines format
+type jsonRecord struct {
+	Type         string    `json:"type"`
+	Target       string    `json:"target"`
+	Points       int       `json:"points"`
+	IsUser       bool      `json:"is_user"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// eventRecord is a line of the JSON Lines format holding a point change
+type eventRecord struct {
+	Type      string    `json:"type"`
+	Target    string    `json:"target"`
+	Timestamp time.Time `json:"timestamp"`
+	Giver     string    `json:"giver"`
+	Channel   string    `json:"channel,omitempty"`
+	Delta     int       `json:"delta"`
+	Dampened  bool      `json:"dampened,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// seasonRecord is a line of the JSON Lines format holding a season
+type seasonRecord struct {
+	Type  string    `json:"type"`
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// standingRecord is a line of the JSON Lines format holding the final points of a target in a season
+type standingRecord struct {
+	Type   string `json:"type"`
+	Season string `json:"season"`
+	Target string `json:"target"`
+	Points int    `json:"points"`
+	IsUser bool   `json:"is_user"`
+}
+
+// badgeRecord is a line of the JSON Lines format holding a badge awarded to a user
+type badgeRecord struct {
+	Type      string    `json:"type"`
+	Target    string    `json:"target"`
+	Badge     string    `json:"badge"`
+	AwardedAt time.Time `json:"awarded_at"`
+}
+
+// streakRecord is a line of the JSON Lines format holding the giving streak of a user
+type streakRecord struct {
+	Type    string `json:"type"`
+	Target  string `json:"target"`
+	Current int    `json:"current"`
+	Longest int    `json:"longest"`
+	LastDay string `json:"last_day"`
+}
+
+// jsonHandlers store the records read from the JSON Lines format, one function per record type
+type jsonHandlers struct {
+	points   func(repository.UserPoints) error
+	event    func(repository.PointEvent) error
+	season   func(repository.Season) error
+	standing func(season string, standing repository.UserPoints) error
+	badge    func(repository.UserBadge) error
+	streak   func(repository.GivingStreak) error
+}
+
+// ParseFormat parses a format name
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "csv":
+		return CSVFormat, nil
+	case "jsonl", "ndjson", "json":
+		return JSONLinesFormat, nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s", s)
+	}
+}
+
+// FormatFromPath guesses the format from a file extension, defaulting to JSON Lines
+func FormatFromPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return CSVFormat
+	}
+	return JSONLinesFormat
+}
+
+// Export writes every points record in the repository to w and returns the number of records written.
+// The JSON Lines format also includes the point history, seasons with their standings, giving streaks and the
+// badges of users. Counters of given points are not written, since Import rebuilds them from the point history.
+// User and channel settings are not exported.
+func Export(ctx context.Context, repo repository.UserPointsRepository, w io.Writer, format Format) (int, error) {
+	count := 0
+
+	switch format {
+	case CSVFormat:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return 0, err
+		}
+		err := repo.ScanPoints(ctx, func(p repository.UserPoints) error {
+			count++
+			return cw.Write([]string{
+				p.UserID,
+				strconv.Itoa(p.Points),
+				strconv.FormatBool(p.IsUser),
+				p.LastModified.UTC().Format(time.RFC3339),
+			})
+		})
+		if err != nil {
+			return count, err
+		}
+		cw.Flush()
+		return count, cw.Error()
+	case JSONLinesFormat:
+		enc := json.NewEncoder(w)
+		encode := func(record any) error {
+			count++
+			return enc.Encode(record)
+		}
+		// Badges are listed per user, so users are collected from every record that can name one
+		users := map[string]bool{}
+		err := repo.ScanPoints(ctx, func(p repository.UserPoints) error {
+			if p.IsUser {
+				users[p.UserID] = true
+			}
+			return encode(jsonRecord{
+				Type:         pointsRecordType,
+				Target:       p.UserID,
+				Points:       p.Points,
+				IsUser:       p.IsUser,
+				LastModified: p.LastModified.UTC(),
+			})
+		})
+		if err != nil {
+			return count, err
+		}
+		err = repo.ScanPointEvents(ctx, func(e repository.PointEvent) error {
+			return encode(eventRecord{
+				Type:      eventRecordType,
+				Target:    e.Target,
+				Timestamp: e.Timestamp.UTC(),
+				Giver:     e.Giver,
+				Channel:   e.Channel,
+				Delta:     e.Delta,
+				Dampened:  e.Dampened,
+				Reason:    e.Reason,
+			})
+		})
+		if err != nil {
+			return count, err
+		}
+		return count, exportExtras(ctx, repo, users, encode)
+	default:
+		return 0, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// exportExtras writes the seasons with their standings, the giving streaks and the badges of users
+func exportExtras(ctx context.Context, repo repository.UserPointsRepository, users map[string]bool, encode func(any) error) error {
+	seasons, err := repo.ListSeasons(ctx)
+	if err != nil {
+		return err
+	}
+	for _, season := range seasons {
+		if err := encode(seasonRecord{Type: seasonRecordType, Name: season.Name, Start: season.Start.UTC(), End: season.End.UTC()}); err != nil {
+			return err
+		}
+		standings, err := repo.ListSeasonStandings(ctx, season.Name, 0)
+		if err != nil {
+			return err
+		}
+		for _, p := range standings {
+			if err := encode(standingRecord{Type: standingRecordType, Season: season.Name, Target: p.UserID, Points: p.Points, IsUser: p.IsUser}); err != nil {
+				return err
+			}
+		}
+	}
+
+	streaks, err := repo.ListGivingStreaks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, streak := range streaks {
+		users[streak.UserID] = true
+		if err := encode(streakRecord{Type: streakRecordType, Target: streak.UserID, Current: streak.Current, Longest: streak.Longest, LastDay: streak.LastDay}); err != nil {
+			return err
+		}
+	}
+
+	givers, err := repo.ListGivers(ctx, 0)
+	if err != nil {
+		return err
+	}
+	for _, giver := range givers {
+		users[giver.UserID] = true
+	}
+
+	ids := make([]string, 0, len(users))
+	for id := range users {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		badges, err := repo.ListBadges(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, badge := range badges {
+			if err := encode(badgeRecord{Type: badgeRecordType, Target: id, Badge: badge.Badge, AwardedAt: badge.AwardedAt.UTC()}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Import reads points records from r and stores them in the repository, overwriting existing
+// records for the same targets. Point history in the JSON Lines format is added, skipping changes already
+// recorded, and the points given in a channel in the changes added are counted for their givers, as the bot
+// counts them. Seasons, standings and giving streaks overwrite existing ones, and badges are awarded unless
+// already awarded. Importing the same data twice yields the same result. It returns the number of records imported.
+func Import(ctx context.Context, repo repository.UserPointsRepository, r io.Reader, format Format) (int, error) {
+	count := 0
+	put := func(p repository.UserPoints) error {
+		if p.UserID == "" {
+			return errors.New("target is empty")
+		}
+		if err := repo.PutUserPoints(ctx, p); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+
+	switch format {
+	case CSVFormat:
+		return count, importCSV(r, put)
+	case JSONLinesFormat:
+		// recorded holds the timestamps of the changes recorded for each target, loaded on first use, so that
+		// given points are only counted for changes not recorded yet
+		recorded := map[string]map[int64]bool{}
+		addEvent := func(e repository.PointEvent) error {
+			if e.Target == "" || e.Timestamp.IsZero() {
+				return errors.New("target or timestamp is empty")
+			}
+			if recorded[e.Target] == nil {
+				events, err := repo.ListPointEvents(ctx, e.Target, time.Time{})
+				if err != nil {
+					return err
+				}
+				recorded[e.Target] = map[int64]bool{}
+				for _, event := range events {
+					recorded[e.Target][event.Timestamp.UnixNano()] = true
+				}
+			}
+			isNew := !recorded[e.Target][e.Timestamp.UnixNano()]
+
+			if err := repo.AddPointEvent(ctx, e); err != nil {
+				return err
+			}
+			recorded[e.Target][e.Timestamp.UnixNano()] = true
+			// Admin changes have no channel and are not given by anyone
+			if isNew && e.Delta > 0 && e.Giver != "" && e.Channel != "" {
+				if err := repo.AddGivenPoints(ctx, e.Giver, e.Target, e.Delta); err != nil {
+					return err
+				}
+			}
+			count++
+			return nil
+		}
+		counted := func(err error) error {
+			if err == nil {
+				count++
+			}
+			return err
+		}
+		return count, importJSONLines(r, jsonHandlers{
+			points: put,
+			event:  addEvent,
+			season: func(season repository.Season) error {
+				if season.Name == "" {
+					return errors.New("season name is empty")
+				}
+				return counted(repo.PutSeason(ctx, season))
+			},
+			standing: func(season string, standing repository.UserPoints) error {
+				if season == "" || standing.UserID == "" {
+					return errors.New("season or target is empty")
+				}
+				return counted(repo.PutSeasonStandings(ctx, season, []repository.UserPoints{standing}))
+			},
+			badge: func(badge repository.UserBadge) error {
+				if badge.UserID == "" || badge.Badge == "" {
+					return errors.New("target or badge is empty")
+				}
+				_, err := repo.AddBadge(ctx, badge)
+				return counted(err)
+			},
+			streak: func(streak repository.GivingStreak) error {
+				if streak.UserID == "" {
+					return errors.New("target is empty")
+				}
+				return counted(repo.PutGivingStreak(ctx, streak))
+			},
+		})
+	default:
+		return 0, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// importCSV parses CSV records and calls put for each of them
+func importCSV(r io.Reader, put func(repository.UserPoints) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i, name := range csvHeader {
+		if header[i] != name {
+			return fmt.Errorf("unexpected CSV header: %v", header)
+		}
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line, _ := cr.FieldPos(0)
+		points, err := strconv.Atoi(row[1])
+		if err != nil {
+			return fmt.Errorf("line %d: invalid points: %w", line, err)
+		}
+		isUser, err := strconv.ParseBool(row[2])
+		if err != nil {
+			return fmt.Errorf("line %d: invalid is_user: %w", line, err)
+		}
+		var lastModified time.Time
+		if row[3] != "" {
+			lastModified, err = time.Parse(time.RFC3339, row[3])
+			if err != nil {
+				return fmt.Errorf("line %d: invalid last_modified: %w", line, err)
+			}
+		}
+
+		if err := put(repository.UserPoints{
+			UserID:       row[0],
+			Points:       points,
+			IsUser:       isUser,
+			LastModified: lastModified,
+		}); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+}
+
+// importJSONLines parses JSON Lines records and calls the handler of the type of each of them
+func importJSONLines(r io.Reader, handlers jsonHandlers) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record jsonRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		switch record.Type {
+		case pointsRecordType, "":
+			if err := handlers.points(repository.UserPoints{
+				UserID:       record.Target,
+				Points:       record.Points,
+				IsUser:       record.IsUser,
+				LastModified: record.LastModified,
+			}); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		case eventRecordType:
+			var event eventRecord
+			if err := json.Unmarshal([]byte(text), &event); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := handlers.event(repository.PointEvent{
+				Target:    event.Target,
+				Timestamp: event.Timestamp,
+				Giver:     event.Giver,
+				Channel:   event.Channel,
+				Delta:     event.Delta,
+				Dampened:  event.Dampened,
+				Reason:    event.Reason,
+			}); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		case seasonRecordType:
+			var season seasonRecord
+			if err := json.Unmarshal([]byte(text), &season); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := handlers.season(repository.Season{Name: season.Name, Start: season.Start, End: season.End}); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		case standingRecordType:
+			var standing standingRecord
+			if err := json.Unmarshal([]byte(text), &standing); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := handlers.standing(standing.Season, repository.UserPoints{
+				UserID: standing.Target,
+				Points: standing.Points,
+				IsUser: standing.IsUser,
+			}); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		case badgeRecordType:
+			var badge badgeRecord
+			if err := json.Unmarshal([]byte(text), &badge); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := handlers.badge(repository.UserBadge{UserID: badge.Target, Badge: badge.Badge, AwardedAt: badge.AwardedAt}); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		case streakRecordType:
+			var streak streakRecord
+			if err := json.Unmarshal([]byte(text), &streak); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := handlers.streak(repository.GivingStreak{
+				UserID:  streak.Target,
+				Current: streak.Current,
+				Longest: streak.Longest,
+				LastDay: streak.LastDay,
+			}); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		default:
+			return fmt.Errorf("line %d: unknown record type %q", line, record.Type)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,240 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"log/slog"
+	"plusplusbot/infra/repository"
+)
+
+func setupTestRepository(t *testing.T) (repository.UserPointsRepository, func()) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	repo, err := repository.NewSQLiteRepository(":memory:", logger)
+	if err != nil {
+		t.Fatalf("Failed to create test repository: %v", err)
+	}
+
+	cleanup := func() {
+		if err := repo.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}
+
+	return repo, cleanup
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	lastModified := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+
+	for _, format := range []Format{CSVFormat, JSONLinesFormat} {
+		t.Run(string(format), func(t *testing.T) {
+			src, cleanupSrc := setupTestRepository(t)
+			defer cleanupSrc()
+			dst, cleanupDst := setupTestRepository(t)
+			defer cleanupDst()
+
+			records := []repository.UserPoints{
+				{UserID: "U111", Points: 12, IsUser: true, LastModified: lastModified},
+				{UserID: "sake", Points: -3, IsUser: false, LastModified: lastModified},
+			}
+			for _, r := range records {
+				if err := src.PutUserPoints(ctx, r); err != nil {
+					t.Fatalf("PutUserPoints() error = %v", err)
+				}
+			}
+
+			var buf bytes.Buffer
+			count, err := Export(ctx, src, &buf, format)
+			if err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if count != len(records) {
+				t.Errorf("Export() = %v, want %v", count, len(records))
+			}
+
+			// Importing twice must not change the result
+			for i := 0; i < 2; i++ {
+				count, err = Import(ctx, dst, bytes.NewReader(buf.Bytes()), format)
+				if err != nil {
+					t.Fatalf("Import() error = %v", err)
+				}
+				if count != len(records) {
+					t.Errorf("Import() = %v, want %v", count, len(records))
+				}
+			}
+
+			for _, want := range records {
+				got, err := dst.GetUserPoints(ctx, want.UserID)
+				if err != nil {
+					t.Fatalf("GetUserPoints() error = %v", err)
+				}
+				if got.Points != want.Points || got.IsUser != want.IsUser || !got.LastModified.Equal(want.LastModified) {
+					t.Errorf("GetUserPoints() = %+v, want %+v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExportImportPointEvents(t *testing.T) {
+	ctx := context.Background()
+	src, cleanupSrc := setupTestRepository(t)
+	defer cleanupSrc()
+	dst, cleanupDst := setupTestRepository(t)
+	defer cleanupDst()
+
+	timestamp := time.Date(2025, 5, 16, 12, 0, 0, 123456789, time.UTC)
+	events := []repository.PointEvent{
+		{Target: "U222", Timestamp: timestamp, Giver: "U111", Channel: "C1", Delta: 1, Reason: "for the review"},
+		{Target: "U111", Timestamp: timestamp.Add(time.Second), Giver: "U222", Channel: "C1", Dampened: true},
+	}
+	for _, e := range events {
+		if err := src.AddPointEvent(ctx, e); err != nil {
+			t.Fatalf("AddPointEvent() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if count, err := Export(ctx, src, &buf, JSONLinesFormat); err != nil || count != len(events) {
+		t.Fatalf("Export() = %v, %v, want %d", count, err, len(events))
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := Import(ctx, dst, bytes.NewReader(buf.Bytes()), JSONLinesFormat); err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+	}
+
+	got, err := dst.ListPointEvents(ctx, "", time.Time{})
+	if err != nil {
+		t.Fatalf("ListPointEvents() error = %v", err)
+	}
+	if len(got) != len(events) {
+		t.Fatalf("ListPointEvents() = %+v, want %+v", got, events)
+	}
+	for i, want := range events {
+		if got[i] != want {
+			t.Errorf("ListPointEvents()[%d] = %+v, want %+v", i, got[i], want)
+		}
+	}
+}
+
+func TestExportImportHistory(t *testing.T) {
+	ctx := context.Background()
+	src, cleanupSrc := setupTestRepository(t)
+	defer cleanupSrc()
+	dst, cleanupDst := setupTestRepository(t)
+	defer cleanupDst()
+
+	timestamp := time.Date(2025, 5, 16, 12, 0, 0, 0, time.UTC)
+	events := []repository.PointEvent{
+		{Target: "U222", Timestamp: timestamp, Giver: "U111", Channel: "C1", Delta: 2},
+		{Target: "sake", Timestamp: timestamp.Add(time.Second), Giver: "U111", Channel: "C1", Delta: 1},
+		// Admin changes are recorded without a channel and are not counted as given
+		{Target: "U222", Timestamp: timestamp.Add(2 * time.Second), Giver: "admin", Delta: 5},
+	}
+	for _, e := range events {
+		if err := src.AddPointEvent(ctx, e); err != nil {
+			t.Fatalf("AddPointEvent() error = %v", err)
+		}
+	}
+	season := repository.Season{Name: "spring", Start: timestamp, End: timestamp.Add(time.Hour)}
+	if err := src.PutSeason(ctx, season); err != nil {
+		t.Fatalf("PutSeason() error = %v", err)
+	}
+	if err := src.PutSeasonStandings(ctx, "spring", []repository.UserPoints{{UserID: "U222", Points: 2, IsUser: true}}); err != nil {
+		t.Fatalf("PutSeasonStandings() error = %v", err)
+	}
+	streak := repository.GivingStreak{UserID: "U111", Current: 2, Longest: 3, LastDay: "2025-05-16"}
+	if err := src.PutGivingStreak(ctx, streak); err != nil {
+		t.Fatalf("PutGivingStreak() error = %v", err)
+	}
+	if _, err := src.AddBadge(ctx, repository.UserBadge{UserID: "U111", Badge: "first-give", AwardedAt: timestamp}); err != nil {
+		t.Fatalf("AddBadge() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if count, err := Export(ctx, src, &buf, JSONLinesFormat); err != nil || count != 7 {
+		t.Fatalf("Export() = %v, %v, want 7", count, err)
+	}
+	// Importing twice must not count the given points twice
+	for i := 0; i < 2; i++ {
+		if _, err := Import(ctx, dst, bytes.NewReader(buf.Bytes()), JSONLinesFormat); err != nil {
+			t.Fatalf("Import() error = %v", err)
+		}
+	}
+
+	if stats, err := dst.GetGiverStats(ctx, "U111"); err != nil || stats.Given != 3 || stats.Recipients != 2 {
+		t.Errorf("GetGiverStats(U111) = %+v, %v, want 3 points given to 2 targets", stats, err)
+	}
+	if stats, err := dst.GetGiverStats(ctx, "admin"); err != nil || stats.Given != 0 {
+		t.Errorf("GetGiverStats(admin) = %+v, %v, want nothing given", stats, err)
+	}
+	if given, err := dst.ListGivenPoints(ctx, "U111", 0); err != nil || len(given) != 2 || given[0].UserID != "U222" || given[0].Points != 2 {
+		t.Errorf("ListGivenPoints(U111) = %+v, %v, want 2 points to U222 first", given, err)
+	}
+
+	if seasons, err := dst.ListSeasons(ctx); err != nil || len(seasons) != 1 || seasons[0].Name != "spring" || !seasons[0].End.Equal(season.End) {
+		t.Errorf("ListSeasons() = %+v, %v, want %+v", seasons, err, season)
+	}
+	if standings, err := dst.ListSeasonStandings(ctx, "spring", 0); err != nil || len(standings) != 1 || standings[0].Points != 2 {
+		t.Errorf("ListSeasonStandings() = %+v, %v, want U222 with 2 points", standings, err)
+	}
+	if got, err := dst.GetGivingStreak(ctx, "U111"); err != nil || *got != streak {
+		t.Errorf("GetGivingStreak() = %+v, %v, want %+v", got, err, streak)
+	}
+	if badges, err := dst.ListBadges(ctx, "U111"); err != nil || len(badges) != 1 || badges[0].Badge != "first-give" {
+		t.Errorf("ListBadges() = %+v, %v, want first-give", badges, err)
+	}
+}
+
+func TestImportInvalid(t *testing.T) {
+	repo, cleanup := setupTestRepository(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{"CSV with wrong header", CSVFormat, "id,score,user,time\nU1,1,true,\n"},
+		{"CSV with invalid points", CSVFormat, "target,points,is_user,last_modified\nU1,many,true,\n"},
+		{"CSV with missing column", CSVFormat, "target,points,is_user,last_modified\nU1,1,true\n"},
+		{"JSON Lines with invalid JSON", JSONLinesFormat, "{\"target\":\n"},
+		{"JSON Lines with unknown type", JSONLinesFormat, "{\"type\":\"unknown\",\"target\":\"U1\"}\n"},
+		{"JSON Lines with empty target", JSONLinesFormat, "{\"type\":\"points\",\"points\":1}\n"},
+		{"JSON Lines event without timestamp", JSONLinesFormat, "{\"type\":\"event\",\"target\":\"U1\",\"giver\":\"U2\",\"delta\":1}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Import(ctx, repo, strings.NewReader(tt.input), tt.format); err == nil {
+				t.Error("Import() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"points.csv":   CSVFormat,
+		"points.CSV":   CSVFormat,
+		"points.jsonl": JSONLinesFormat,
+		"":             JSONLinesFormat,
+	}
+	for path, want := range tests {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}